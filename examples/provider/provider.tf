@@ -1,3 +1,8 @@
 provider "launchpad" {
-  # example configuration here
+  # defaults for hosts which don't set their own connection values
+  ssh_user     = "ubuntu"
+  ssh_key_path = "./key.pem"
+
+  apply_concurrency = 20
+  log_level         = "info"
 }
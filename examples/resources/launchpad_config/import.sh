@@ -0,0 +1,5 @@
+# Import an existing cluster using the launchpad.yaml used to install it
+terraform import launchpad_config.example ./launchpad.yaml
+
+# or pass the launchpad.yaml contents inline, base64 encoded
+terraform import launchpad_config.example "base64:$(base64 -w0 ./launchpad.yaml)"
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// launchpadPublicHosts the internet hosts which the mcr and image repo defaults point at.
+var launchpadPublicHosts = []string{"docker.io", "registry-1.docker.io", "repos.mirantis.com", "get.mirantis.com"}
+
+// launchpadPublicEndpoint does a URL, or an image repo such as docker.io/mirantis, point at one of the public hosts.
+func launchpadPublicEndpoint(endpoint string) bool {
+	host := endpoint
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return false
+		}
+		host = u.Hostname()
+	} else if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+
+	for _, h := range launchpadPublicHosts {
+		if strings.EqualFold(host, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// launchpadAirgapCheck refuse an airgapped cluster which still installs from the public repos, and return the path of the attribute to fix.
+//
+// The windows install script is only downloaded when there are winrm hosts.
+func launchpadAirgapCheck(pls launchpadModel) (path.Path, error) {
+	if !pls.Airgap() {
+		return path.Empty(), nil
+	}
+
+	endpoints := map[string]string{
+		"spec.mcr.repo_url":          pls.Spec.MCR.RepoURL.ValueString(),
+		"spec.mcr.install_url_linux": pls.Spec.MCR.InstallURLLinux.ValueString(),
+		"spec.mke.image_repo":        pls.Spec.MKE.ImageRepo.ValueString(),
+	}
+	for _, h := range pls.Spec.Hosts {
+		if len(h.WinRM) > 0 {
+			endpoints["spec.mcr.install_url_windows"] = pls.Spec.MCR.InstallURLWindows.ValueString()
+		}
+	}
+	for _, msr := range pls.Spec.MSR {
+		endpoints["spec.msr.image_repo"] = msr.ImageRepo.ValueString()
+	}
+
+	public := []string{}
+	for name, endpoint := range endpoints {
+		if launchpadPublicEndpoint(endpoint) {
+			public = append(public, fmt.Sprintf("%s (%s)", name, endpoint))
+		}
+	}
+	if len(public) > 0 {
+		return path.Root("spec").AtName("airgap"), fmt.Errorf("an airgapped cluster cannot use the public defaults, set local mirrors for: %s", strings.Join(launchpadSorted(public), ", "))
+	}
+
+	if bundle := pls.AirgapImageBundle(); bundle != "" && !launchpadImageBundleName(bundle) {
+		return path.Root("spec").AtName("airgap").AtListIndex(0).AtName("image_bundle"), fmt.Errorf("the airgap image bundle %s has to be a .tar, .tar.gz or .tgz file", bundle)
+	}
+
+	return path.Empty(), nil
+}
+
+// launchpadImageBundleName does the file name have one of the image bundle suffixes: .tar, .tar.gz or .tgz.
+func launchpadImageBundleName(name string) bool {
+	for _, suffix := range []string{".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeAirgapImageDir hard link, or else copy, the airgap image bundle into a temporary image dir, and use it for the hosts which have no image_dir of their own.
+//
+// Launchpad only loads images from a directory of bundles. The returned cleanup removes the
+// directory again, and has to be called once launchpad is done, even when this fails.
+func writeAirgapImageDir(ls launchpadModel, cc *mcc_mke_api.ClusterConfig) (func(), error) {
+	cleanup := func() {}
+
+	bundle := ls.AirgapImageBundle()
+	if bundle == "" {
+		return cleanup, nil
+	}
+
+	bundle, err := filepath.Abs(bundle)
+	if err != nil {
+		return cleanup, fmt.Errorf("could not resolve the airgap image bundle: %w", err)
+	}
+	if info, err := os.Stat(bundle); err != nil {
+		return cleanup, fmt.Errorf("could not read the airgap image bundle: %w", err)
+	} else if !info.Mode().IsRegular() {
+		return cleanup, fmt.Errorf("the airgap image bundle %s is not a file", bundle)
+	}
+
+	// next to the bundle, so that it can be hard linked rather than copied
+	dir, err := os.MkdirTemp(filepath.Dir(bundle), ".launchpad-images-*")
+	if err != nil {
+		if dir, err = os.MkdirTemp("", "launchpad-images-*"); err != nil {
+			return cleanup, fmt.Errorf("could not create a temporary image dir: %w", err)
+		}
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+
+	// launchpad only loads .tar and .gz files, and sizes them with Lstat, which would only see a symlink
+	name := filepath.Base(bundle)
+	if strings.HasSuffix(name, ".tgz") {
+		name = strings.TrimSuffix(name, ".tgz") + ".tar.gz"
+	}
+	link := filepath.Join(dir, name)
+	if err := os.Link(bundle, link); err != nil {
+		if err := copyFile(bundle, link); err != nil {
+			return cleanup, fmt.Errorf("could not copy the airgap image bundle: %w", err)
+		}
+	}
+
+	for _, h := range cc.Spec.Hosts {
+		if h.ImageDir == "" {
+			h.ImageDir = dir
+		}
+	}
+
+	return cleanup, nil
+}
+
+// copyFile copy the file at src to a new file at dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestLaunchpadPublicEndpoint(t *testing.T) {
+	for endpoint, public := range map[string]bool{
+		"https://repos.mirantis.com":          true,
+		"https://get.mirantis.com/":           true,
+		"docker.io/mirantis":                  true,
+		"https://mirror.example.org/mirantis": false,
+		"registry.example.org:5000/mirantis":  false,
+		"file:///opt/mcr/install.sh":          false,
+		"":                                    false,
+	} {
+		if launchpadPublicEndpoint(endpoint) != public {
+			t.Errorf("expected %q public %t", endpoint, public)
+		}
+	}
+}
+
+func TestLaunchpadAirgapCheck(t *testing.T) {
+	pls := testLaunchpadDiffModel()
+	pls.Spec.MCR.RepoURL = types.StringValue("https://repos.mirantis.com")
+	pls.Spec.MCR.InstallURLLinux = types.StringValue("https://get.mirantis.com/")
+	pls.Spec.MCR.InstallURLWindows = types.StringValue("https://get.mirantis.com/install.ps1")
+	pls.Spec.MSR[0].ImageRepo = types.StringValue("docker.io/mirantis")
+	if _, err := launchpadAirgapCheck(pls); err != nil {
+		t.Errorf("unexpected error without airgap: %s", err)
+	}
+
+	pls.Spec.Airgap = []launchpadModelSpecAirgap{{ImageBundle: types.StringNull()}}
+	p, err := launchpadAirgapCheck(pls)
+	if err == nil {
+		t.Fatal("expected an error for the public defaults")
+	}
+	for _, name := range []string{"spec.mcr.repo_url", "spec.mcr.install_url_linux", "spec.mke.image_repo", "spec.msr.image_repo"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("expected %s in the error: %s", name, err)
+		}
+	}
+	if !p.Equal(path.Root("spec").AtName("airgap")) {
+		t.Errorf("expected the error on spec.airgap, got %s", p)
+	}
+	if strings.Contains(err.Error(), "install_url_windows") {
+		t.Errorf("did not expect the windows install script without winrm hosts: %s", err)
+	}
+
+	pls.Spec.MCR.RepoURL = types.StringValue("https://mirror.example.org/mcr")
+	pls.Spec.MCR.InstallURLLinux = types.StringValue("file:///opt/mcr/install.sh")
+	pls.Spec.MKE.ImageRepo = types.StringValue("registry.example.org/mirantis")
+	pls.Spec.MSR[0].ImageRepo = types.StringValue("registry.example.org/mirantis")
+	if _, err := launchpadAirgapCheck(pls); err != nil {
+		t.Errorf("unexpected error with local mirrors: %s", err)
+	}
+
+	for _, bundle := range []string{"./images.tar", "./images.tar.gz", "./images.tgz"} {
+		pls.Spec.Airgap[0].ImageBundle = types.StringValue(bundle)
+		if _, err := launchpadAirgapCheck(pls); err != nil {
+			t.Errorf("unexpected error for the image bundle %s: %s", bundle, err)
+		}
+	}
+
+	pls.Spec.Airgap[0].ImageBundle = types.StringValue("./images.gz")
+	if _, err := launchpadAirgapCheck(pls); err == nil {
+		t.Error("expected an error for a gzip file which is not a tarball")
+	}
+
+	pls.Spec.Airgap[0].ImageBundle = types.StringValue("./images.zip")
+	if p, err := launchpadAirgapCheck(pls); err == nil {
+		t.Error("expected an error for an image bundle which is not a tarball")
+	} else if expected := path.Root("spec").AtName("airgap").AtListIndex(0).AtName("image_bundle"); !p.Equal(expected) {
+		t.Errorf("expected the error on %s, got %s", expected, p)
+	}
+}
+
+func TestWriteAirgapImageDir(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "mke_images.tar.gz")
+	if err := os.WriteFile(bundle, []byte("images"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ls := testLaunchpadDiffModel()
+	ls.Spec.Airgap = []launchpadModelSpecAirgap{{ImageBundle: types.StringValue(bundle)}}
+	ls.Spec.Hosts[1].ImageDir = types.StringValue("./worker-images")
+
+	cc, err := ls.ClusterConfig(&diag.Diagnostics{})
+	if err != nil {
+		t.Fatalf("unexpected cluster config error: %s", err)
+	}
+
+	cleanup, err := writeAirgapImageDir(ls, &cc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	dir := cc.Spec.Hosts[0].ImageDir
+	if b, err := os.ReadFile(filepath.Join(dir, "mke_images.tar.gz")); err != nil || string(b) != "images" {
+		t.Errorf("expected the image bundle in the image dir: %v", err)
+	}
+	if info, err := os.Lstat(filepath.Join(dir, "mke_images.tar.gz")); err != nil || !info.Mode().IsRegular() || info.Size() != int64(len("images")) {
+		t.Errorf("expected launchpad to see the bundle file itself, not a link: %v", info)
+	}
+	if cc.Spec.Hosts[1].ImageDir != "./worker-images" {
+		t.Errorf("expected the host image_dir to be kept, got %s", cc.Spec.Hosts[1].ImageDir)
+	}
+
+	cleanup()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("expected the temporary image dir to be removed")
+	}
+	if _, err := os.Stat(bundle); err != nil {
+		t.Error("expected the image bundle itself to be kept")
+	}
+
+	ls.Spec.Airgap[0].ImageBundle = types.StringValue(filepath.Join(t.TempDir(), "missing.tar"))
+	cleanup, err = writeAirgapImageDir(ls, &cc)
+	defer cleanup()
+	if err == nil {
+		t.Error("expected an error for a missing image bundle")
+	}
+}
+
+func TestWriteAirgapImageDirTgz(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "mke_images.tgz")
+	if err := os.WriteFile(bundle, []byte("images"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ls := testLaunchpadDiffModel()
+	ls.Spec.Airgap = []launchpadModelSpecAirgap{{ImageBundle: types.StringValue(bundle)}}
+
+	cc, err := ls.ClusterConfig(&diag.Diagnostics{})
+	if err != nil {
+		t.Fatalf("unexpected cluster config error: %s", err)
+	}
+
+	cleanup, err := writeAirgapImageDir(ls, &cc)
+	defer cleanup()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// launchpad skips files which do not end in .tar or .gz
+	if b, err := os.ReadFile(filepath.Join(cc.Spec.Hosts[0].ImageDir, "mke_images.tar.gz")); err != nil || string(b) != "images" {
+		t.Errorf("expected the .tgz bundle in the image dir as a .tar.gz: %v", err)
+	}
+}
+
+func TestCopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "images.tar"), filepath.Join(dir, "copy.tar")
+	if err := os.WriteFile(src, []byte("images"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := copyFile(src, dst); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if info, err := os.Lstat(dst); err != nil || !info.Mode().IsRegular() {
+		t.Errorf("expected a regular file copy: %v", err)
+	}
+	if b, err := os.ReadFile(dst); err != nil || string(b) != "images" {
+		t.Errorf("expected the copied contents: %v", err)
+	}
+
+	if err := copyFile(src, dst); err == nil {
+		t.Error("expected an error when the copy already exists")
+	}
+}
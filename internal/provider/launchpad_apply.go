@@ -0,0 +1,146 @@
+package provider
+
+import (
+	mcc_analytics "github.com/Mirantis/mcc/pkg/analytics"
+	mcc_phase "github.com/Mirantis/mcc/pkg/phase"
+	mcc_common_phase "github.com/Mirantis/mcc/pkg/product/common/phase"
+	mcc_mke "github.com/Mirantis/mcc/pkg/product/mke"
+	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
+	mcc_mke_phase "github.com/Mirantis/mcc/pkg/product/mke/phase"
+)
+
+// launchpadPhase a launchpad (mcc) phase, as the mcc phase manager runs it.
+type launchpadPhase interface {
+	Run() error
+	Title() string
+}
+
+// launchpadInstall run launchpad apply for a new cluster.
+//
+// An airgapped cluster gets the same phases through launchpadApply, which leaves out the
+// launchpad upgrade check and analytics.
+func launchpadInstall(c *mcc_mke.MKE, disableCleanup, force, airgap bool, concurrency int) error {
+	if !airgap {
+		return c.Apply(disableCleanup, force, concurrency)
+	}
+
+	d := newLaunchpadDiff()
+	d.Add(launchpadChangeInstall, "MCR", "MKE", "MSR")
+	return launchpadApply(&c.ClusterConfig, d, disableCleanup, force, airgap, concurrency)
+}
+
+// launchpadApply run launchpad apply for a set of changes, using only the phases which the changes need.
+//
+// Airgapped runs turn off the launchpad analytics. They are process global, so they stay off,
+// rather than being turned back on under another airgapped run.
+func launchpadApply(cc *mcc_mke_api.ClusterConfig, d launchpadDiff, disableCleanup, force, airgap bool, concurrency int) error {
+	if airgap {
+		mcc_analytics.Enabled(false)
+	}
+
+	phaseManager := mcc_phase.NewManager(cc)
+	phaseManager.SkipCleanup = disableCleanup
+
+	for _, p := range launchpadApplyPhases(d, force, airgap, concurrency) {
+		phaseManager.AddPhase(p)
+	}
+
+	return phaseManager.Run()
+}
+
+// launchpadApplyPhases the launchpad apply phases needed for a set of changes.
+//
+// New hosts, removed hosts and unclassified changes get the same phases as launchpad apply
+// (mcc_mke.MKE.Apply). Product upgrades only get the phases that gather facts and upgrade
+// the product, and MCR config changes only the phases that write daemon.json and restart MCR.
+// Airgapped clusters never get the upgrade check, which needs docker hub. Hook and credential changes need no phases at all.
+func launchpadApplyPhases(d launchpadDiff, force, airgap bool, concurrency int) []launchpadPhase {
+	if !d.NeedsApply() {
+		return []launchpadPhase{}
+	}
+
+	if d.NeedsFullApply() {
+		phases := []launchpadPhase{}
+		if !airgap {
+			// asks docker hub for the latest MKE release
+			phases = append(phases, &mcc_mke_phase.UpgradeCheck{})
+		}
+		return append(phases,
+			&mcc_common_phase.Connect{},
+			&mcc_mke_phase.DetectOS{},
+			&mcc_mke_phase.GatherFacts{},
+			&mcc_mke_phase.ValidateFacts{Force: force},
+			&mcc_mke_phase.ValidateHosts{},
+			&mcc_mke_phase.DownloadInstaller{},
+			&mcc_common_phase.RunHooks{Stage: "before", Action: "apply"},
+			&mcc_mke_phase.PrepareHost{},
+			&mcc_mke_phase.ConfigureMCR{},
+			&mcc_mke_phase.InstallMCR{},
+			&mcc_mke_phase.UpgradeMCR{Concurrency: concurrency},
+			&mcc_mke_phase.RestartMCR{},
+			&mcc_mke_phase.LoadImages{},
+			&mcc_mke_phase.AuthenticateDocker{},
+			&mcc_mke_phase.PullMKEImages{},
+			&mcc_mke_phase.InitSwarm{},
+			&mcc_mke_phase.InstallMKE{},
+			&mcc_mke_phase.UpgradeMKE{},
+			&mcc_mke_phase.JoinManagers{},
+			&mcc_mke_phase.JoinWorkers{},
+			&mcc_mke_phase.PullMSRImages{},
+			&mcc_mke_phase.ValidateMKEHealth{},
+			&mcc_mke_phase.InstallMSR{},
+			&mcc_mke_phase.UpgradeMSR{},
+			&mcc_mke_phase.JoinMSRReplicas{},
+			&mcc_mke_phase.LabelNodes{},
+			&mcc_mke_phase.RemoveNodes{},
+			&mcc_common_phase.RunHooks{Stage: "after", Action: "apply"},
+			&mcc_common_phase.Disconnect{},
+			&mcc_mke_phase.Info{},
+		)
+	}
+
+	phases := []launchpadPhase{
+		&mcc_common_phase.Connect{},
+		&mcc_mke_phase.DetectOS{},
+		&mcc_mke_phase.GatherFacts{},
+		&mcc_mke_phase.ValidateFacts{Force: force},
+		&mcc_common_phase.RunHooks{Stage: "before", Action: "apply"},
+	}
+
+	if d.Has(launchpadChangeMCRConfig) {
+		phases = append(phases, &mcc_mke_phase.ConfigureMCR{})
+	}
+	if d.Has(launchpadChangeMCRUpgrade) {
+		phases = append(phases,
+			&mcc_mke_phase.DownloadInstaller{},
+			&mcc_mke_phase.UpgradeMCR{Concurrency: concurrency},
+		)
+	}
+	if d.Has(launchpadChangeMCRConfig) || d.Has(launchpadChangeMCRUpgrade) {
+		// only restarts the hosts which were upgraded, or whose daemon.json changed
+		phases = append(phases, &mcc_mke_phase.RestartMCR{})
+	}
+	if d.Has(launchpadChangeMKEUpgrade) || d.Has(launchpadChangeMSRUpgrade) {
+		// airgapped hosts get the new images from their image_dir
+		phases = append(phases, &mcc_mke_phase.LoadImages{})
+	}
+	if d.Has(launchpadChangeMKEUpgrade) {
+		phases = append(phases,
+			&mcc_mke_phase.AuthenticateDocker{},
+			&mcc_mke_phase.PullMKEImages{},
+			&mcc_mke_phase.UpgradeMKE{},
+		)
+	}
+	if d.Has(launchpadChangeMSRUpgrade) {
+		phases = append(phases,
+			&mcc_mke_phase.PullMSRImages{},
+			&mcc_mke_phase.ValidateMKEHealth{},
+			&mcc_mke_phase.UpgradeMSR{},
+		)
+	}
+
+	return append(phases,
+		&mcc_common_phase.RunHooks{Stage: "after", Action: "apply"},
+		&mcc_common_phase.Disconnect{},
+	)
+}
@@ -0,0 +1,535 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	mcc_common_api "github.com/Mirantis/mcc/pkg/product/common/api"
+	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
+	k0s_dig "github.com/k0sproject/dig"
+	k0s_rig "github.com/k0sproject/rig"
+)
+
+// ClusterConfig convert this state object into a proper ClusterConfig.
+//
+// Conversion problems, such as list elements that are not strings, are appended to diags.
+func (ls launchpadModel) ClusterConfig(diags *diag.Diagnostics) (mcc_mke_api.ClusterConfig, error) {
+	cc := mcc_mke_api.ClusterConfig{
+		APIVersion: LaunchpadClusterConfigAPIVersion,
+		Kind:       "mke",
+
+		Metadata: func() *mcc_mke_api.ClusterMeta {
+			return &mcc_mke_api.ClusterMeta{
+				Name: ls.Metadata.Name.ValueString(),
+			}
+		}(),
+
+		Spec: &mcc_mke_api.ClusterSpec{
+			Cluster: mcc_mke_api.Cluster{
+				Prune: ls.Prune(),
+			},
+
+			Hosts: mcc_mke_api.Hosts{},
+
+			MCR: mcc_common_api.MCRConfig{
+				Version:           ls.Spec.MCR.Version.ValueString(),
+				InstallURLLinux:   ls.Spec.MCR.InstallURLLinux.ValueString(),
+				InstallURLWindows: ls.Spec.MCR.InstallURLWindows.ValueString(),
+				RepoURL:           ls.Spec.MCR.RepoURL.ValueString(),
+				Channel:           ls.Spec.MCR.Channel.ValueString(),
+			},
+
+			MKE: mcc_mke_api.MKEConfig{
+				AdminUsername:   ls.Spec.MKE.AdminUsername.ValueString(),
+				AdminPassword:   ls.Spec.MKE.AdminPassword.ValueString(),
+				ImageRepo:       ls.Spec.MKE.ImageRepo.ValueString(),
+				Version:         ls.Spec.MKE.Version.ValueString(),
+				InstallFlags:    mcc_common_api.Flags{},
+				UpgradeFlags:    mcc_common_api.Flags{},
+				Metadata:        &mcc_mke_api.MKEMetadata{},
+				LicenseFilePath: ls.Spec.MKE.LicenseFilePath.ValueString(),
+				ConfigData:      ls.Spec.MKE.ConfigData.ValueString(),
+				CACertPath:      ls.Spec.MKE.CACertPath.ValueString(),
+				CertPath:        ls.Spec.MKE.CertPath.ValueString(),
+				KeyPath:         ls.Spec.MKE.KeyPath.ValueString(),
+			},
+
+			MSR: nil,
+		},
+	}
+
+	var err error
+	if cc.Spec.MKE.CACertData, err = launchpadFileData(ls.Spec.MKE.CACertData, ls.Spec.MKE.CACertPath); err != nil {
+		return cc, fmt.Errorf("MKE CA certificate: %w", err)
+	}
+	if cc.Spec.MKE.CertData, err = launchpadFileData(ls.Spec.MKE.CertData, ls.Spec.MKE.CertPath); err != nil {
+		return cc, fmt.Errorf("MKE TLS certificate: %w", err)
+	}
+	if cc.Spec.MKE.KeyData, err = launchpadFileData(ls.Spec.MKE.KeyData, ls.Spec.MKE.KeyPath); err != nil {
+		return cc, fmt.Errorf("MKE TLS key: %w", err)
+	}
+
+	if len(ls.Spec.MKE.CloudProvider) > 0 {
+		cp := ls.Spec.MKE.CloudProvider[0]
+
+		configData, err := launchpadFileData(cp.ConfigData, cp.ConfigFile)
+		if err != nil {
+			return cc, fmt.Errorf("MKE cloud provider config: %w", err)
+		}
+		cc.Spec.MKE.Cloud = &mcc_mke_api.MKECloud{
+			Provider:   cp.Provider.ValueString(),
+			ConfigFile: cp.ConfigFile.ValueString(),
+			ConfigData: configData,
+		}
+	}
+
+	for _, msr := range ls.Spec.MSR {
+		hasMSRHosts := false
+		for _, host := range ls.Spec.Hosts {
+			if host.Role.ValueString() == HostRoleMSR {
+				hasMSRHosts = true
+			}
+		}
+		if hasMSRHosts {
+			cc.Spec.MSR = &mcc_mke_api.MSRConfig{
+				ImageRepo:    msr.ImageRepo.ValueString(),
+				Version:      msr.Version.ValueString(),
+				ReplicaIDs:   msr.ReplicaIDs.ValueString(),
+				CACertData:   msr.CACertData.ValueString(),
+				CertData:     msr.CertData.ValueString(),
+				KeyData:      msr.KeyData.ValueString(),
+				InstallFlags: mcc_common_api.Flags{},
+				UpgradeFlags: mcc_common_api.Flags{},
+			}
+
+			if !msr.InstallFlags.IsNull() {
+				var fvs []string
+				ds := msr.InstallFlags.ElementsAs(context.Background(), &fvs, true)
+				diags.Append(ds...)
+				if !ds.HasError() {
+					cc.Spec.MSR.InstallFlags = mcc_common_api.Flags(fvs)
+				}
+			}
+			if !msr.UpgradeFlags.IsNull() {
+				var fvs []string
+				ds := msr.UpgradeFlags.ElementsAs(context.Background(), &fvs, true)
+				diags.Append(ds...)
+				if !ds.HasError() {
+					cc.Spec.MSR.UpgradeFlags = mcc_common_api.Flags(fvs)
+				}
+			}
+		} else {
+			diags.AddWarning("MSR configuration without hosts", "MSR configuration was provided, however there are no hosts with the MSR role provided. MSR installation is skippet.")
+		}
+	}
+
+	if !ls.Spec.MKE.InstallFlags.IsNull() {
+		var fvs []string
+		ds := ls.Spec.MKE.InstallFlags.ElementsAs(context.Background(), &fvs, true)
+		diags.Append(ds...)
+		if !ds.HasError() {
+			cc.Spec.MKE.InstallFlags = mcc_common_api.Flags(fvs)
+		}
+	}
+	if !ls.Spec.MKE.UpgradeFlags.IsNull() {
+		var fvs []string
+		ds := ls.Spec.MKE.UpgradeFlags.ElementsAs(context.Background(), &fvs, true)
+		diags.Append(ds...)
+		if !ds.HasError() {
+			cc.Spec.MKE.UpgradeFlags = mcc_common_api.Flags(fvs)
+		}
+	}
+	// TODO: add a nodes_health_retry attribute for MKEConfig.NodesHealthRetry, once the bundled mcc has it
+	if !ls.Spec.MKE.SwarmInstallFlags.IsNull() {
+		var fvs []string
+		ds := ls.Spec.MKE.SwarmInstallFlags.ElementsAs(context.Background(), &fvs, true)
+		diags.Append(ds...)
+		if !ds.HasError() {
+			cc.Spec.MKE.SwarmInstallFlags = mcc_common_api.Flags(fvs)
+		}
+	}
+	if !ls.Spec.MKE.SwarmUpdateCommands.IsNull() {
+		var cvs []string
+		ds := ls.Spec.MKE.SwarmUpdateCommands.ElementsAs(context.Background(), &cvs, true)
+		diags.Append(ds...)
+		if !ds.HasError() {
+			cc.Spec.MKE.SwarmUpdateCommands = cvs
+		}
+	}
+
+	for _, host := range ls.Spec.Hosts {
+		mccHost := mcc_mke_api.Host{
+			Role:             host.Role.ValueString(),
+			Hooks:            mcc_common_api.Hooks{},
+			ImageDir:         host.ImageDir.ValueString(),
+			PrivateInterface: host.PrivateInterface.ValueString(),
+		}
+
+		daemonConfig, err := host.MCRDaemonConfig(ls.Spec.MCR)
+		if err != nil {
+			return cc, fmt.Errorf("host %s: %w", host.Address(), err)
+		}
+		mccHost.DaemonConfig = daemonConfig
+
+		if !host.Environment.IsNull() {
+			var env map[string]string
+			ds := host.Environment.ElementsAs(context.Background(), &env, true)
+			diags.Append(ds...)
+			if !ds.HasError() {
+				mccHost.Environment = env
+			}
+		}
+
+		if len(host.SSH) > 0 {
+			hssh := host.SSH[0]
+
+			mccHost.Connection = k0s_rig.Connection{
+				SSH: &k0s_rig.SSH{
+					Address: hssh.Address.ValueString(),
+					KeyPath: hssh.KeyPath.ValueStringPointer(),
+					User:    hssh.User.ValueString(),
+					Port:    int(hssh.Port.ValueInt64()),
+				},
+			}
+
+			if hssh.UseAgent.ValueBool() || hssh.PrivateKey.ValueString() != "" {
+				// an empty key path keeps the provider ssh_key_path out, and makes rig use the ssh agent.
+				// A private key is written to a temporary key path before launchpad runs.
+				mccHost.SSH.KeyPath = new(string)
+			}
+			if len(hssh.Bastion) > 0 {
+				mccHost.SSH.Bastion = hssh.Bastion[0].SSH()
+			}
+			if hk := hssh.HostKey.ValueString(); hk != "" {
+				hostKey, err := launchpadHostKey(hk)
+				if err != nil {
+					return cc, fmt.Errorf("host %s: %w", hssh.Address.ValueString(), err)
+				}
+				mccHost.SSH.HostKey = hostKey
+			}
+		} else if len(host.WinRM) > 0 {
+			hwinrm := host.WinRM[0]
+
+			mccHost.Connection = k0s_rig.Connection{
+				WinRM: &k0s_rig.WinRM{
+					Address:  hwinrm.Address.ValueString(),
+					Password: hwinrm.Password.ValueString(),
+					User:     hwinrm.User.ValueString(),
+					Port:     int(hwinrm.Port.ValueInt64()),
+					UseHTTPS: hwinrm.UseHTTPS.ValueBool(),
+					Insecure: hwinrm.Insecure.ValueBool(),
+
+					CACertPath:    hwinrm.CACertPath.ValueString(),
+					CertPath:      hwinrm.CertPath.ValueString(),
+					KeyPath:       hwinrm.KeyPath.ValueString(),
+					TLSServerName: hwinrm.TLSServerName.ValueString(),
+				},
+			}
+		}
+
+		if len(host.Hooks) > 0 {
+			sh := host.Hooks[0]
+
+			if len(sh.Apply) > 0 {
+				ha := sh.Apply[0]
+
+				hha := map[string][]string{
+					"before": {},
+					"after":  {},
+				}
+				var shab []string
+				ds := ha.Before.ElementsAs(context.Background(), &shab, true)
+				diags.Append(ds...)
+				if !ds.HasError() {
+					hha["before"] = shab
+				}
+				var shaa []string
+				ds = ha.After.ElementsAs(context.Background(), &shaa, true)
+				diags.Append(ds...)
+				if !ds.HasError() {
+					hha["after"] = shaa
+				}
+
+				mccHost.Hooks["apply"] = hha
+			}
+
+			if len(sh.Reset) > 0 {
+				hr := sh.Reset[0]
+
+				hhr := map[string][]string{
+					"before": {},
+					"after":  {},
+				}
+				var shrb []string
+				ds := hr.Before.ElementsAs(context.Background(), &shrb, true)
+				diags.Append(ds...)
+				if !ds.HasError() {
+					hhr["before"] = shrb
+				}
+				var shra []string
+				ds = hr.After.ElementsAs(context.Background(), &shra, true)
+				diags.Append(ds...)
+				if !ds.HasError() {
+					hhr["after"] = shra
+				}
+
+				mccHost.Hooks["reset"] = hhr
+			}
+		}
+
+		cc.Spec.Hosts = append(cc.Spec.Hosts, &mccHost)
+	}
+
+	return cc, nil
+}
+
+// WithoutFiles this state object without the MKE TLS and cloud provider config file paths, so that its ClusterConfig reads no local files.
+//
+// Read and Delete only connect to the hosts, and the files may be gone by then, such as when
+// the certificates were rotated, or the destroy runs on another machine. Inline data is kept.
+func (ls launchpadModel) WithoutFiles() launchpadModel {
+	ls.Spec.MKE.CACertPath = types.StringNull()
+	ls.Spec.MKE.CertPath = types.StringNull()
+	ls.Spec.MKE.KeyPath = types.StringNull()
+
+	cps := make([]launchpadModelSpecMKECloudProvider, len(ls.Spec.MKE.CloudProvider))
+	for i, cp := range ls.Spec.MKE.CloudProvider {
+		cp.ConfigFile = types.StringNull()
+		cps[i] = cp
+	}
+	ls.Spec.MKE.CloudProvider = cps
+
+	return ls
+}
+
+// ResolveComputed fill in computed values which were left unknown in the plan, from the ClusterConfig that was used.
+func (ls *launchpadModel) ResolveComputed(cc mcc_mke_api.ClusterConfig) {
+	for i, host := range ls.Spec.Hosts {
+		if i >= len(cc.Spec.Hosts) {
+			break
+		}
+		mccHost := cc.Spec.Hosts[i]
+
+		for j, hssh := range host.SSH {
+			if hssh.Port.IsUnknown() && mccHost.SSH != nil {
+				ls.Spec.Hosts[i].SSH[j].Port = types.Int64Value(int64(mccHost.SSH.Port))
+			}
+		}
+	}
+}
+
+// launchpadModelFromClusterConfig convert a launchpad ClusterConfig into a state object, as is needed for import.
+func launchpadModelFromClusterConfig(ctx context.Context, cc mcc_mke_api.ClusterConfig) (launchpadModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	stringList := func(vs []string) types.List {
+		if len(vs) == 0 {
+			return types.ListNull(types.StringType)
+		}
+		l, ds := types.ListValueFrom(ctx, types.StringType, vs)
+		diags.Append(ds...)
+		return l
+	}
+	stringMap := func(vs map[string]string) types.Map {
+		if len(vs) == 0 {
+			return types.MapNull(types.StringType)
+		}
+		m, ds := types.MapValueFrom(ctx, types.StringType, vs)
+		diags.Append(ds...)
+		return m
+	}
+	daemonConfig := func(dc k0s_dig.Mapping) types.String {
+		if len(dc) == 0 {
+			return types.StringNull()
+		}
+		b, err := json.Marshal(dc)
+		if err != nil {
+			diags.AddError("Could not convert the host MCR daemon config", err.Error())
+			return types.StringNull()
+		}
+		return types.StringValue(string(b))
+	}
+	optionalString := func(v string) types.String {
+		if v == "" {
+			return types.StringNull()
+		}
+		return types.StringValue(v)
+	}
+	fileData := func(data, path string) string {
+		if path != "" {
+			return ""
+		}
+		return data
+	}
+
+	ls := launchpadModel{
+		Id:               types.StringValue(cc.Metadata.Name),
+		SkipDestroy:      types.BoolValue(false),
+		ApplyConcurrency: types.Int64Null(),
+		Force:            types.BoolValue(false),
+		DisableCleanup:   types.BoolValue(false),
+		LogFile:          types.StringNull(),
+		PlannedActions:   types.ListValueMust(types.StringType, []attr.Value{}),
+
+		Metadata: launchpadModelMetadata{
+			Name: types.StringValue(cc.Metadata.Name),
+		},
+
+		Spec: launchpadModelSpec{
+			Cluster: []launchpadModelCluster{},
+			Airgap:  []launchpadModelSpecAirgap{},
+
+			MCR: launchpadModelSpecMCR{
+				Version:           types.StringValue(cc.Spec.MCR.Version),
+				Channel:           types.StringValue(cc.Spec.MCR.Channel),
+				InstallURLLinux:   types.StringValue(cc.Spec.MCR.InstallURLLinux),
+				InstallURLWindows: types.StringValue(cc.Spec.MCR.InstallURLWindows),
+				RepoURL:           types.StringValue(cc.Spec.MCR.RepoURL),
+				DaemonConfig:      types.StringNull(),
+			},
+
+			MKE: launchpadModelSpecMKE{
+				AdminPassword:       types.StringValue(cc.Spec.MKE.AdminPassword),
+				AdminUsername:       types.StringValue(cc.Spec.MKE.AdminUsername),
+				ImageRepo:           types.StringValue(cc.Spec.MKE.ImageRepo),
+				Version:             types.StringValue(cc.Spec.MKE.Version),
+				InstallFlags:        stringList(cc.Spec.MKE.InstallFlags),
+				UpgradeFlags:        stringList(cc.Spec.MKE.UpgradeFlags),
+				SwarmInstallFlags:   stringList(cc.Spec.MKE.SwarmInstallFlags),
+				SwarmUpdateCommands: stringList(cc.Spec.MKE.SwarmUpdateCommands),
+				LicenseFilePath:     types.StringValue(cc.Spec.MKE.LicenseFilePath),
+				ConfigData:          optionalString(cc.Spec.MKE.ConfigData),
+				CACertPath:          optionalString(cc.Spec.MKE.CACertPath),
+				CertPath:            optionalString(cc.Spec.MKE.CertPath),
+				KeyPath:             optionalString(cc.Spec.MKE.KeyPath),
+				// launchpad loads the paths into the data, keep only the path so that they don't conflict
+				CACertData: optionalString(fileData(cc.Spec.MKE.CACertData, cc.Spec.MKE.CACertPath)),
+				CertData:   optionalString(fileData(cc.Spec.MKE.CertData, cc.Spec.MKE.CertPath)),
+				KeyData:    optionalString(fileData(cc.Spec.MKE.KeyData, cc.Spec.MKE.KeyPath)),
+
+				CloudProvider: []launchpadModelSpecMKECloudProvider{},
+			},
+
+			MSR:   []launchpadModelSpecMSR{},
+			Hosts: []launchpadModelSpecHost{},
+		},
+	}
+
+	if cloud := cc.Spec.MKE.Cloud; cloud != nil {
+		ls.Spec.MKE.CloudProvider = append(ls.Spec.MKE.CloudProvider, launchpadModelSpecMKECloudProvider{
+			Provider:   types.StringValue(cloud.Provider),
+			ConfigFile: optionalString(cloud.ConfigFile),
+			ConfigData: optionalString(fileData(cloud.ConfigData, cloud.ConfigFile)),
+		})
+	}
+
+	if cc.Spec.Cluster.Prune {
+		ls.Spec.Cluster = append(ls.Spec.Cluster, launchpadModelCluster{
+			Prune:             types.BoolValue(true),
+			ResetRemovedHosts: types.BoolValue(false),
+		})
+	}
+
+	if msr := cc.Spec.MSR; msr != nil {
+		ls.Spec.MSR = append(ls.Spec.MSR, launchpadModelSpecMSR{
+			ImageRepo:    types.StringValue(msr.ImageRepo),
+			Version:      types.StringValue(msr.Version),
+			ReplicaIDs:   types.StringValue(msr.ReplicaIDs),
+			CACertData:   optionalString(msr.CACertData),
+			CertData:     optionalString(msr.CertData),
+			KeyData:      optionalString(msr.KeyData),
+			InstallFlags: stringList(msr.InstallFlags),
+			UpgradeFlags: stringList(msr.UpgradeFlags),
+		})
+	}
+
+	for _, mccHost := range cc.Spec.Hosts {
+		host := launchpadModelSpecHost{
+			Role:             types.StringValue(mccHost.Role),
+			Environment:      stringMap(mccHost.Environment),
+			ImageDir:         optionalString(mccHost.ImageDir),
+			PrivateInterface: optionalString(mccHost.PrivateInterface),
+			DaemonConfig:     daemonConfig(mccHost.DaemonConfig),
+			Hooks:            []launchpadModelSpecHostHooks{},
+			SSH:              []launchpadModelSpecHostSSH{},
+			WinRM:            []launchpadModelSpecHostWinrm{},
+		}
+
+		hha, hasApply := mccHost.Hooks["apply"]
+		hhr, hasReset := mccHost.Hooks["reset"]
+		if hasApply || hasReset {
+			hooks := launchpadModelSpecHostHooks{
+				Apply: []launchpadModelSpecHostHookAction{},
+				Reset: []launchpadModelSpecHostHookAction{},
+			}
+			if hasApply {
+				hooks.Apply = append(hooks.Apply, launchpadModelSpecHostHookAction{
+					Before: stringList(hha["before"]),
+					After:  stringList(hha["after"]),
+				})
+			}
+			if hasReset {
+				hooks.Reset = append(hooks.Reset, launchpadModelSpecHostHookAction{
+					Before: stringList(hhr["before"]),
+					After:  stringList(hhr["after"]),
+				})
+			}
+			host.Hooks = append(host.Hooks, hooks)
+		}
+
+		if hssh := mccHost.SSH; hssh != nil {
+			bastions := []launchpadSSHBastionModel{}
+			if b := hssh.Bastion; b != nil {
+				port := types.Int64Null()
+				if b.Port != 0 {
+					port = types.Int64Value(int64(b.Port))
+				}
+				bastions = append(bastions, launchpadSSHBastionModel{
+					Address:    types.StringValue(b.Address),
+					User:       optionalString(b.User),
+					Port:       port,
+					KeyPath:    types.StringPointerValue(b.KeyPath),
+					PrivateKey: types.StringNull(),
+					HostKey:    optionalString(b.HostKey),
+				})
+			}
+
+			host.SSH = append(host.SSH, launchpadModelSpecHostSSH{
+				Address:    types.StringValue(hssh.Address),
+				KeyPath:    types.StringPointerValue(hssh.KeyPath),
+				PrivateKey: types.StringNull(),
+				UseAgent:   types.BoolValue(false),
+				HostKey:    optionalString(hssh.HostKey),
+				User:       types.StringValue(hssh.User),
+				Port:       types.Int64Value(int64(hssh.Port)),
+				Bastion:    bastions,
+			})
+		} else if hwinrm := mccHost.WinRM; hwinrm != nil {
+			host.WinRM = append(host.WinRM, launchpadModelSpecHostWinrm{
+				Address:  types.StringValue(hwinrm.Address),
+				User:     types.StringValue(hwinrm.User),
+				Password: types.StringValue(hwinrm.Password),
+				Port:     types.Int64Value(int64(hwinrm.Port)),
+				UseHTTPS: types.BoolValue(hwinrm.UseHTTPS),
+				Insecure: types.BoolValue(hwinrm.Insecure),
+
+				CACertPath:    optionalString(hwinrm.CACertPath),
+				CACertData:    types.StringNull(),
+				CertPath:      optionalString(hwinrm.CertPath),
+				CertData:      types.StringNull(),
+				KeyPath:       optionalString(hwinrm.KeyPath),
+				KeyData:       types.StringNull(),
+				TLSServerName: optionalString(hwinrm.TLSServerName),
+			})
+		}
+
+		ls.Spec.Hosts = append(ls.Spec.Hosts, host)
+	}
+
+	return ls, diags
+}
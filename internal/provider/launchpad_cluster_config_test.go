@@ -0,0 +1,283 @@
+package provider
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v2"
+)
+
+// updateGolden regenerate the golden files in testdata, instead of comparing with them.
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// testStringList a terraform string list.
+func testStringList(vs ...string) types.List {
+	elements := []attr.Value{}
+	for _, v := range vs {
+		elements = append(elements, types.StringValue(v))
+	}
+	return types.ListValueMust(types.StringType, elements)
+}
+
+// testLaunchpadModel a complete state for the cluster config conversion tests, with a single manager.
+func testLaunchpadModel() launchpadModel {
+	return launchpadModel{
+		Metadata: launchpadModelMetadata{Name: types.StringValue("test")},
+		Spec: launchpadModelSpec{
+			Cluster: []launchpadModelCluster{},
+			MCR: launchpadModelSpecMCR{
+				Version:           types.StringValue("23.0"),
+				Channel:           types.StringValue("stable"),
+				InstallURLLinux:   types.StringValue("https://get.mirantis.com/"),
+				InstallURLWindows: types.StringValue("https://get.mirantis.com/install.ps1"),
+				RepoURL:           types.StringValue("https://repos.mirantis.com"),
+			},
+			MKE: launchpadModelSpecMKE{
+				AdminPassword:   types.StringValue("mypassword"),
+				AdminUsername:   types.StringValue("admin"),
+				ImageRepo:       types.StringValue("docker.io/mirantis"),
+				Version:         types.StringValue("3.6.4"),
+				InstallFlags:    types.ListNull(types.StringType),
+				UpgradeFlags:    types.ListNull(types.StringType),
+				LicenseFilePath: types.StringValue(""),
+				ConfigData:      types.StringNull(),
+			},
+			MSR: []launchpadModelSpecMSR{},
+			Hosts: []launchpadModelSpecHost{
+				{
+					Role:  types.StringValue("manager"),
+					Hooks: []launchpadModelSpecHostHooks{},
+					SSH: []launchpadModelSpecHostSSH{{
+						Address: types.StringValue("manager1.example.org"),
+						KeyPath: types.StringValue("./key.pem"),
+						User:    types.StringValue("ubuntu"),
+						Port:    types.Int64Value(22),
+					}},
+					WinRM: []launchpadModelSpecHostWinrm{},
+				},
+			},
+		},
+	}
+}
+
+func TestLaunchpadModelClusterConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(ls *launchpadModel)
+	}{
+		{
+			name:   "ssh",
+			change: func(ls *launchpadModel) {},
+		},
+		{
+			name: "ssh_bastion",
+			change: func(ls *launchpadModel) {
+				ls.Spec.Hosts[0].SSH[0].Bastion = []launchpadSSHBastionModel{{
+					Address:    types.StringValue("jump.example.org"),
+					User:       types.StringValue("jump"),
+					Port:       types.Int64Value(2222),
+					KeyPath:    types.StringValue("./jump.pem"),
+					PrivateKey: types.StringNull(),
+				}}
+			},
+		},
+		{
+			name: "hooks",
+			change: func(ls *launchpadModel) {
+				ls.Spec.Hosts[0].Hooks = []launchpadModelSpecHostHooks{{
+					Apply: []launchpadModelSpecHostHookAction{{
+						Before: testStringList("ls -la", "pwd"),
+						After:  testStringList("docker ps"),
+					}},
+					Reset: []launchpadModelSpecHostHookAction{{
+						Before: testStringList("umount /mnt/data"),
+						After:  types.ListNull(types.StringType),
+					}},
+				}}
+			},
+		},
+		{
+			name: "winrm",
+			change: func(ls *launchpadModel) {
+				ls.Spec.Hosts = append(ls.Spec.Hosts, launchpadModelSpecHost{
+					Role:  types.StringValue("worker"),
+					Hooks: []launchpadModelSpecHostHooks{},
+					SSH:   []launchpadModelSpecHostSSH{},
+					WinRM: []launchpadModelSpecHostWinrm{{
+						Address:  types.StringValue("windowsworker1.example.org"),
+						User:     types.StringValue("Administrator"),
+						Password: types.StringValue("my-win-password"),
+						Port:     types.Int64Value(5986),
+						UseHTTPS: types.BoolValue(true),
+						Insecure: types.BoolValue(false),
+
+						CACertPath:    types.StringValue("./ca.pem"),
+						TLSServerName: types.StringValue("windowsworker1.internal"),
+					}},
+				})
+			},
+		},
+		{
+			name: "mke_flags",
+			change: func(ls *launchpadModel) {
+				ls.Spec.MKE.InstallFlags = testStringList("--san=mke.example.org", "--default-node-orchestrator=kubernetes")
+				ls.Spec.MKE.UpgradeFlags = testStringList("--force-minimums")
+				ls.Spec.MKE.ConfigData = types.StringValue("[scheduling_configuration]")
+				ls.Spec.MKE.SwarmInstallFlags = testStringList("--data-path-port=7789")
+				ls.Spec.MKE.SwarmUpdateCommands = testStringList("swarm update --cert-expiry 2160h")
+			},
+		},
+		{
+			name: "mke_cloud_provider",
+			change: func(ls *launchpadModel) {
+				ls.Spec.MKE.CloudProvider = []launchpadModelSpecMKECloudProvider{{
+					Provider:   types.StringValue("azure"),
+					ConfigFile: types.StringNull(),
+					ConfigData: types.StringValue("{\"cloud\": \"AzurePublicCloud\"}"),
+				}}
+			},
+		},
+		{
+			name: "msr_flags",
+			change: func(ls *launchpadModel) {
+				ls.Spec.MSR = []launchpadModelSpecMSR{{
+					ImageRepo:    types.StringValue("docker.io/mirantis"),
+					Version:      types.StringValue("2.9.4"),
+					ReplicaIDs:   types.StringValue("sequential"),
+					CACertData:   types.StringNull(),
+					CertData:     types.StringNull(),
+					KeyData:      types.StringNull(),
+					InstallFlags: testStringList("--ucp-insecure-tls", "--dtr-external-url=msr.example.org"),
+					UpgradeFlags: testStringList("--debug"),
+				}}
+				ls.Spec.Hosts = append(ls.Spec.Hosts, launchpadModelSpecHost{
+					Role:  types.StringValue("msr"),
+					Hooks: []launchpadModelSpecHostHooks{},
+					SSH: []launchpadModelSpecHostSSH{{
+						Address: types.StringValue("msr1.example.org"),
+						KeyPath: types.StringValue("./key.pem"),
+						User:    types.StringValue("ubuntu"),
+						Port:    types.Int64Value(22),
+					}},
+					WinRM: []launchpadModelSpecHostWinrm{},
+				})
+			},
+		},
+		{
+			name: "host_options",
+			change: func(ls *launchpadModel) {
+				ls.Spec.Hosts[0].Environment = types.MapValueMust(types.StringType, map[string]attr.Value{
+					"HTTP_PROXY": types.StringValue("http://proxy.example.org:3128"),
+					"NO_PROXY":   types.StringValue("localhost,10.0.0.0/8"),
+				})
+				ls.Spec.Hosts[0].ImageDir = types.StringValue("./images")
+				ls.Spec.Hosts[0].PrivateInterface = types.StringValue("eth1")
+				ls.Spec.Hosts[0].DaemonConfig = types.StringValue(`{"registry-mirrors":["https://mirror.example.org"]}`)
+				ls.Spec.MCR.DaemonConfig = types.StringValue(`{"log-driver":"journald","log-opts":{"tag":"{{.Name}}"}}`)
+			},
+		},
+		{
+			name: "prune",
+			change: func(ls *launchpadModel) {
+				ls.Spec.Cluster = []launchpadModelCluster{{
+					Prune:             types.BoolValue(true),
+					ResetRemovedHosts: types.BoolValue(false),
+				}}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ls := testLaunchpadModel()
+			test.change(&ls)
+
+			var diags diag.Diagnostics
+			cc, err := ls.ClusterConfig(&diags)
+			if err != nil {
+				t.Fatalf("unexpected cluster config error: %s", err)
+			}
+			if diags.ErrorsCount() > 0 || diags.WarningsCount() > 0 {
+				t.Fatalf("unexpected cluster config diagnostics: %v", diags)
+			}
+			out, err := yaml.Marshal(cc)
+			if err != nil {
+				t.Fatalf("could not marshal the cluster config: %s", err)
+			}
+
+			golden := filepath.Join("testdata", "cluster_config", test.name+".yaml")
+			if *updateGolden {
+				if err := os.WriteFile(golden, out, 0600); err != nil {
+					t.Fatalf("could not update the golden file: %s", err)
+				}
+			}
+
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("could not read the golden file: %s", err)
+			}
+			if string(out) != string(expected) {
+				t.Errorf("cluster config does not match %s:\n%s", golden, out)
+			}
+		})
+	}
+}
+
+func TestLaunchpadModelClusterConfigDiagnostics(t *testing.T) {
+	ls := testLaunchpadModel()
+	ls.Spec.MSR = []launchpadModelSpecMSR{{
+		Version:      types.StringValue("2.9.4"),
+		InstallFlags: types.ListNull(types.StringType),
+		UpgradeFlags: types.ListNull(types.StringType),
+	}}
+	ls.Spec.MKE.InstallFlags = types.ListValueMust(types.Int64Type, []attr.Value{types.Int64Value(1)})
+
+	var diags diag.Diagnostics
+	cc, err := ls.ClusterConfig(&diags)
+	if err != nil {
+		t.Fatalf("unexpected cluster config error: %s", err)
+	}
+
+	if diags.WarningsCount() != 1 || diags.Warnings()[0].Summary() != "MSR configuration without hosts" {
+		t.Errorf("expected a warning for MSR without hosts, got %v", diags.Warnings())
+	}
+	if cc.Spec.MSR != nil {
+		t.Error("did not expect MSR without hosts")
+	}
+	if !diags.HasError() {
+		t.Error("expected an error for MKE install flags which are not strings")
+	}
+}
+
+func TestLaunchpadModelWithoutFiles(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+
+	ls := testLaunchpadModel()
+	ls.Spec.MKE.CACertPath = types.StringValue(missing)
+	ls.Spec.MKE.CertPath = types.StringValue(missing)
+	ls.Spec.MKE.KeyPath = types.StringValue(missing)
+	ls.Spec.MKE.CloudProvider = []launchpadModelSpecMKECloudProvider{{
+		Provider:   types.StringValue("openstack"),
+		ConfigFile: types.StringValue(missing),
+		ConfigData: types.StringNull(),
+	}}
+	if _, err := ls.ClusterConfig(&diag.Diagnostics{}); err == nil {
+		t.Fatal("expected an error for the missing files")
+	}
+
+	cc, err := ls.WithoutFiles().ClusterConfig(&diag.Diagnostics{})
+	if err != nil {
+		t.Fatalf("unexpected cluster config error without the files: %s", err)
+	}
+	if cc.Spec.MKE.CertPath != "" || cc.Spec.MKE.Cloud == nil || cc.Spec.MKE.Cloud.Provider != "openstack" || cc.Spec.MKE.Cloud.ConfigFile != "" {
+		t.Errorf("expected the cluster config without the file paths, got %+v", cc.Spec.MKE)
+	}
+	if ls.Spec.MKE.CloudProvider[0].ConfigFile.ValueString() != missing {
+		t.Error("did not expect the cloud provider config file to be dropped from the original model")
+	}
+}
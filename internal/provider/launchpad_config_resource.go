@@ -1,21 +1,29 @@
 package provider
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"gopkg.in/yaml.v2"
 
 	mcc_mke "github.com/Mirantis/mcc/pkg/product/mke"
-	mcc_logrus "github.com/sirupsen/logrus"
+	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
 )
 
 var _ resource.Resource = &LaunchpadConfigResource{}
+var _ resource.ResourceWithImportState = &LaunchpadConfigResource{}
+var _ resource.ResourceWithUpgradeState = &LaunchpadConfigResource{}
+var _ resource.ResourceWithModifyPlan = &LaunchpadConfigResource{}
 
 type LaunchpadConfigResource struct {
-	testingMode bool
+	testingMode   bool
+	providerModel *LaunchpadProviderModel
 }
 
 func NewLaunchpadConfigResource() resource.Resource {
@@ -27,7 +35,7 @@ func (r *LaunchpadConfigResource) Metadata(ctx context.Context, req resource.Met
 }
 
 func (r *LaunchpadConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = launchpadSchema14()
+	resp.Schema = launchpadSchema()
 }
 
 func (r *LaunchpadConfigResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -48,10 +56,191 @@ func (r *LaunchpadConfigResource) Configure(ctx context.Context, req resource.Co
 	}
 
 	r.testingMode = lpm.testingMode
+	r.providerModel = lpm
+}
+
+func (r *LaunchpadConfigResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to preview on destroy, or when nothing changed at all
+	if req.Plan.Raw.IsNull() || req.Plan.Raw.Equal(req.State.Raw) {
+		return
+	}
+
+	var pls launchpadModel
+	var sls *launchpadModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &pls)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &sls)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// with unknown values, such as host addresses of hosts which are still being created, the
+	// changes and the certificate names cannot be worked out yet, so only the model is checked
+	known := req.Plan.Raw.IsFullyKnown()
+
+	if sls != nil && known {
+		if err := launchpadHostRemovalCheck(*sls, pls); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("spec").AtName("host"),
+				"Refusing to remove cluster managers",
+				fmt.Sprintf("%s. Keep one of the current managers in the spec, and remove it in a later apply once the new managers have joined.", err.Error()),
+			)
+
+			return
+		}
+
+		if removed := launchpadRemovedHosts(*sls, pls); len(removed) > 0 {
+			detail := "These hosts are no longer in the spec, and will be removed from the swarm:\n - " + strings.Join(removed, "\n - ")
+			if pls.Prune() {
+				detail += "\n\nWith spec.cluster.prune, launchpad also removes any other swarm node which is not in the spec, such as nodes joined outside of terraform. Those are not listed here, and are not counted by the check that a manager remains."
+			}
+			resp.Diagnostics.AddWarning(
+				"Hosts will be removed from the cluster",
+				detail,
+			)
+		}
+
+		if launchpadCloudProviderChanged(*sls, pls) {
+			resp.Diagnostics.AddWarning(
+				"MKE cloud provider changes will not be applied",
+				"MKE is already installed, and launchpad only sets up spec.mke.cloud_provider when it installs MKE.",
+			)
+		}
+
+		if launchpadSwarmChanged(*sls, pls) {
+			resp.Diagnostics.AddWarning(
+				"Swarm install flags will not be applied",
+				"The swarm is already initialized, and launchpad only uses spec.mke.swarm_install_flags and spec.mke.swarm_update_commands when it initializes the swarm.",
+			)
+		}
+
+		if launchpadMSRInstallFlagsChanged(*sls, pls) {
+			resp.Diagnostics.AddWarning(
+				"MSR install flags will not be applied",
+				"MSR is already installed, and launchpad only uses spec.msr.install_flags when it installs MSR. Existing MSR replicas keep their configuration, use spec.msr.upgrade_flags for flags to pass to MSR upgrades.",
+			)
+		}
+	}
+
+	if p, err := launchpadSSHAuthCheck(pls); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			p,
+			"Invalid host ssh authentication",
+			err.Error(),
+		)
+
+		return
+	}
+
+	if p, err := launchpadHostKeyCheck(pls); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			p,
+			"Invalid host key",
+			err.Error(),
+		)
+
+		return
+	}
+
+	if p, err := launchpadWinRMCertCheck(pls); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			p,
+			"Invalid host winrm certificate",
+			err.Error(),
+		)
+
+		return
+	}
+
+	if insecure := launchpadWinRMInsecureHosts(pls); len(insecure) > 0 {
+		resp.Diagnostics.AddWarning(
+			"WinRM certificates will not be verified",
+			"These hosts use winrm over https with insecure = true, so their certificates are not verified. Set insecure = false, with a ca_cert_path or ca_cert_data if the certificates are not publicly trusted:\n - "+strings.Join(insecure, "\n - "),
+		)
+	}
+
+	if p, err := launchpadDaemonConfigCheck(pls); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			p,
+			"Invalid MCR daemon config",
+			err.Error(),
+		)
+
+		return
+	}
+
+	if p, err := launchpadAirgapCheck(pls); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			p,
+			"Invalid airgapped installation",
+			err.Error(),
+		)
+
+		return
+	}
+
+	if err := launchpadSwarmInstallFlagsCheck(pls); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("spec").AtName("mke").AtName("swarm_install_flags"),
+			"Invalid swarm install flags",
+			err.Error(),
+		)
+
+		return
+	}
+
+	if err := launchpadCloudProviderCheck(pls); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("spec").AtName("mke").AtName("cloud_provider"),
+			"Invalid MKE cloud provider",
+			err.Error(),
+		)
+
+		return
+	}
+
+	if !known {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("planned_actions"), types.ListUnknown(types.StringType))...)
+
+		return
+	}
+
+	if pls.HasMKETLS() {
+		if err := launchpadMKETLSCheck(pls); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("spec").AtName("mke"),
+				"Invalid MKE TLS certificate",
+				err.Error(),
+			)
+
+			return
+		}
+	}
+
+	actions := launchpadPlannedActions(sls, pls)
+
+	plannedActions, diags := types.ListValueFrom(ctx, types.StringType, actions)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("planned_actions"), plannedActions)...)
+
+	if len(actions) == 0 {
+		return
+	}
+
+	summary := "Launchpad will install the cluster"
+	if sls != nil {
+		summary = "Launchpad will not run for the cluster changes"
+		if d := sls.Diff(pls); d.NeedsApply() {
+			summary = "Launchpad will run for the cluster changes"
+		}
+	}
+	resp.Diagnostics.AddWarning(summary, "Planned actions:\n - "+strings.Join(actions, "\n - "))
 }
 
 func (r *LaunchpadConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var cls *launchpadSchema14Model
+	var cls *launchpadModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &cls)...)
@@ -60,7 +249,7 @@ func (r *LaunchpadConfigResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
-	cc, err := cls.ClusterConfig(resp.Diagnostics)
+	cc, err := cls.ClusterConfig(&resp.Diagnostics)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to build cluster config from terraform config",
@@ -69,6 +258,41 @@ func (r *LaunchpadConfigResource) Create(ctx context.Context, req resource.Creat
 
 		return
 	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.providerModel.ApplyHostDefaults(&cc)
+
+	cleanupKeys, err := writeConnectionFiles(*cls, r.providerModel, &cc)
+	defer cleanupKeys()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to write the connection key files",
+			err.Error(),
+		)
+
+		return
+	}
+
+	cleanupImages, err := writeAirgapImageDir(*cls, &cc)
+	defer cleanupImages()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to prepare the airgap image bundle",
+			err.Error(),
+		)
+
+		return
+	}
+
+	cls.ResolveComputed(cc)
+	if cls.PlannedActions.IsUnknown() {
+		// the plan had unknown values, so the actions are only known now
+		var diags diag.Diagnostics
+		cls.PlannedActions, diags = types.ListValueFrom(ctx, types.StringType, launchpadPlannedActions(nil, *cls))
+		resp.Diagnostics.Append(diags...)
+	}
 
 	c := mcc_mke.MKE{ClusterConfig: cc}
 
@@ -81,16 +305,28 @@ func (r *LaunchpadConfigResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
-	logrusBuffer := &bytes.Buffer{}
-	mcc_logrus.SetOutput(logrusBuffer)
+	if !r.testingMode && !r.verifyHostKeys(&cc, &resp.Diagnostics) {
+		return
+	}
+
+	lpLog, err := startLaunchpadLog(ctx, cls.LogFile.ValueString(), cc)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Launchpad log capture failed",
+			err.Error(),
+		)
+
+		return
+	}
+	defer lpLog.Stop()
 
 	if r.testingMode {
 		resp.Diagnostics.AddWarning("testing mode warning", "launchpad config resource handler is in testing mode, no installation will be run.")
-	} else if err := c.Apply(false, false, 10); err != nil {
-		ccout, _ := yaml.Marshal(cc)
+	} else if err := launchpadInstall(&c, cls.DisableCleanup.ValueBool(), cls.Force.ValueBool(), cls.Airgap(), r.applyConcurrency(*cls)); err != nil {
+		ccout, _ := yaml.Marshal(redactedClusterConfig(cc))
 		resp.Diagnostics.AddError(
 			"Launchpad apply failed",
-			fmt.Sprintf("%s \n\n%s; %s", ccout, err.Error(), logrusBuffer.String()),
+			fmt.Sprintf("%s \n\n%s; %s", ccout, lpLog.Redact(err.Error()), lpLog.String()),
 		)
 
 		return
@@ -104,15 +340,90 @@ func (r *LaunchpadConfigResource) Create(ctx context.Context, req resource.Creat
 }
 
 func (r *LaunchpadConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	// launchpad has no good way to discover existing installation, so we don't do anything
+	var sls launchpadModel
+
+	diags := req.State.Get(ctx, &sls)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.testingMode {
+		// there is no cluster to discover in testing mode, so we keep the state as it is
+		return
+	}
+
+	cc, err := sls.WithoutFiles().ClusterConfig(&resp.Diagnostics)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to build cluster config from terraform state",
+			err.Error(),
+		)
+
+		return
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.providerModel.ApplyHostDefaults(&cc)
+
+	cleanupKeys, err := writeConnectionFiles(sls, r.providerModel, &cc)
+	defer cleanupKeys()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to write the connection key files",
+			err.Error(),
+		)
+
+		return
+	}
+
+	if !r.testingMode && !r.verifyHostKeys(&cc, &resp.Diagnostics) {
+		return
+	}
+
+	lpLog, err := startLaunchpadLog(ctx, sls.LogFile.ValueString(), cc)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Launchpad log capture failed",
+			err.Error(),
+		)
+
+		return
+	}
+	defer lpLog.Stop()
+
+	d, err := discoverCluster(&cc)
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Launchpad cluster discovery failed",
+			fmt.Sprintf("The cluster hosts could not be inspected, so no drift was detected. %s; %s", lpLog.Redact(err.Error()), lpLog.String()),
+		)
+
+		return
+	}
+
+	if !d.Installed() {
+		// nothing is installed on any of the hosts, so the cluster is gone
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	sls.Refresh(d)
+
+	if diags := resp.State.Set(ctx, sls); diags != nil {
+		resp.Diagnostics.Append(diags...)
+	}
 }
 
 func (r *LaunchpadConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// if enough attributes have changed then run apply
-	var cls launchpadSchema14Model
-	var sls launchpadSchema14Model
+	// only run the launchpad phases which the changes need
+	var cls launchpadModel
+	var sls launchpadModel
 
-	if diags := req.Config.Get(ctx, &cls); diags != nil {
+	if diags := req.Plan.Get(ctx, &cls); diags != nil {
 		resp.Diagnostics.Append(diags...)
 	}
 	if diags := req.State.Get(ctx, &sls); diags != nil {
@@ -128,21 +439,60 @@ func (r *LaunchpadConfigResource) Update(ctx context.Context, req resource.Updat
 		return
 	}
 
-	if cls.ClusterEqual(sls) {
+	cc, err := cls.ClusterConfig(&resp.Diagnostics)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to build cluster config from terraform config",
+			err.Error(),
+		)
+
+		return
+	}
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	cc, err := cls.ClusterConfig(resp.Diagnostics)
+	r.providerModel.ApplyHostDefaults(&cc)
+
+	cleanupKeys, err := writeConnectionFiles(cls, r.providerModel, &cc)
+	defer cleanupKeys()
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Failed to build cluster config from terraform config",
+			"Failed to write the connection key files",
 			err.Error(),
 		)
 
 		return
 	}
 
-	c := mcc_mke.MKE{ClusterConfig: cc}
+	cleanupImages, err := writeAirgapImageDir(cls, &cc)
+	defer cleanupImages()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to prepare the airgap image bundle",
+			err.Error(),
+		)
+
+		return
+	}
+
+	cls.ResolveComputed(cc)
+	if cls.PlannedActions.IsUnknown() {
+		// the plan had unknown values, so the actions are only known now
+		var diags diag.Diagnostics
+		cls.PlannedActions, diags = types.ListValueFrom(ctx, types.StringType, launchpadPlannedActions(&sls, cls))
+		resp.Diagnostics.Append(diags...)
+	}
+
+	d := sls.Diff(cls)
+	if !d.NeedsApply() {
+		// hooks and connection credentials are only used when launchpad runs, so only the state changes
+		if diags := resp.State.Set(ctx, cls); diags != nil {
+			resp.Diagnostics.Append(diags...)
+		}
+
+		return
+	}
 
 	if err := cc.Validate(); err != nil {
 		resp.Diagnostics.AddError(
@@ -153,15 +503,33 @@ func (r *LaunchpadConfigResource) Update(ctx context.Context, req resource.Updat
 		return
 	}
 
-	logrusBuffer := &bytes.Buffer{}
-	mcc_logrus.SetOutput(logrusBuffer)
+	if d.Has(launchpadChangeHostRemoved) && !r.testingMode {
+		if !r.removeHosts(ctx, sls, cls, d.Details(launchpadChangeHostRemoved), &resp.Diagnostics) {
+			return
+		}
+	}
+
+	if !r.testingMode && !r.verifyHostKeys(&cc, &resp.Diagnostics) {
+		return
+	}
+
+	lpLog, err := startLaunchpadLog(ctx, cls.LogFile.ValueString(), cc)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Launchpad log capture failed",
+			err.Error(),
+		)
+
+		return
+	}
+	defer lpLog.Stop()
 
 	if r.testingMode {
-		resp.Diagnostics.AddWarning("testing mode warning", "launchpad config resource handler is in testing mode, no update will be run.")
-	} else if err := c.Apply(false, false, 10); err != nil {
+		resp.Diagnostics.AddWarning("testing mode warning", fmt.Sprintf("launchpad config resource handler is in testing mode, no update will be run for: %s", d))
+	} else if err := launchpadApply(&cc, d, cls.DisableCleanup.ValueBool(), cls.Force.ValueBool(), cls.Airgap(), r.applyConcurrency(cls)); err != nil {
 		resp.Diagnostics.AddError(
 			"Launchpad apply failed",
-			fmt.Sprintf("%s; %s", err.Error(), logrusBuffer.String()),
+			fmt.Sprintf("%s; %s", lpLog.Redact(err.Error()), lpLog.String()),
 		)
 
 		return
@@ -173,7 +541,7 @@ func (r *LaunchpadConfigResource) Update(ctx context.Context, req resource.Updat
 }
 
 func (r *LaunchpadConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var sls launchpadSchema14Model
+	var sls launchpadModel
 
 	diags := req.State.Get(ctx, &sls)
 	resp.Diagnostics.Append(diags...)
@@ -190,7 +558,7 @@ func (r *LaunchpadConfigResource) Delete(ctx context.Context, req resource.Delet
 		return
 	}
 
-	cc, err := sls.ClusterConfig(resp.Diagnostics)
+	cc, err := sls.WithoutFiles().ClusterConfig(&resp.Diagnostics)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to build cluster config from terraform config",
@@ -199,18 +567,46 @@ func (r *LaunchpadConfigResource) Delete(ctx context.Context, req resource.Delet
 
 		return
 	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.providerModel.ApplyHostDefaults(&cc)
+
+	cleanupKeys, err := writeConnectionFiles(sls, r.providerModel, &cc)
+	defer cleanupKeys()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to write the connection key files",
+			err.Error(),
+		)
+
+		return
+	}
 
 	c := mcc_mke.MKE{ClusterConfig: cc}
 
-	logrusBuffer := &bytes.Buffer{}
-	mcc_logrus.SetOutput(logrusBuffer)
+	if !r.testingMode && !r.verifyHostKeys(&cc, &resp.Diagnostics) {
+		return
+	}
+
+	lpLog, err := startLaunchpadLog(ctx, sls.LogFile.ValueString(), cc)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Launchpad log capture failed",
+			err.Error(),
+		)
+
+		return
+	}
+	defer lpLog.Stop()
 
 	if r.testingMode {
 		resp.Diagnostics.AddWarning("testing mode warning", "launchpad config resource handler is in testing mode, no reset will be run.")
 	} else if err := c.Reset(); err != nil {
 		resp.Diagnostics.AddError(
 			"Launchpad Reset failed",
-			fmt.Sprintf("%s; %s", err.Error(), logrusBuffer.String()),
+			fmt.Sprintf("%s; %s", lpLog.Redact(err.Error()), lpLog.String()),
 		)
 
 		return
@@ -220,6 +616,160 @@ func (r *LaunchpadConfigResource) Delete(ctx context.Context, req resource.Delet
 	resp.State.RemoveResource(ctx)
 }
 
+// removeHosts gracefully remove hosts which were dropped from the spec, before launchpad applies the new spec.
+//
+// The removal uses the prior state, which still has the connections for the removed hosts.
+// Every removed host gets its own diagnostic, and false is returned if any removal failed.
+func (r *LaunchpadConfigResource) removeHosts(ctx context.Context, sls, cls launchpadModel, addresses []string, diags *diag.Diagnostics) bool {
+	scc, err := sls.ClusterConfig(diags)
+	if err != nil {
+		diags.AddError(
+			"Failed to build cluster config from terraform state",
+			err.Error(),
+		)
+
+		return false
+	}
+	if diags.HasError() {
+		return false
+	}
+
+	r.providerModel.ApplyHostDefaults(&scc)
+
+	cleanupKeys, err := writeConnectionFiles(sls, r.providerModel, &scc)
+	defer cleanupKeys()
+	if err != nil {
+		diags.AddError(
+			"Failed to write the connection key files",
+			err.Error(),
+		)
+
+		return false
+	}
+
+	if !r.testingMode && !r.verifyHostKeys(&scc, diags) {
+		return false
+	}
+
+	lpLog, err := startLaunchpadLog(ctx, cls.LogFile.ValueString(), scc)
+	if err != nil {
+		diags.AddError(
+			"Launchpad log capture failed",
+			err.Error(),
+		)
+
+		return false
+	}
+	defer lpLog.Stop()
+
+	removal, err := removeClusterHosts(&scc, addresses, cls.ResetRemovedHosts())
+	if err != nil {
+		diags.AddError(
+			"Launchpad host removal failed",
+			fmt.Sprintf("%s; %s", lpLog.Redact(err.Error()), lpLog.String()),
+		)
+
+		return false
+	}
+
+	ok := true
+	for _, address := range launchpadSorted(addresses) {
+		steps := strings.Join(removal.Steps(address), ", ")
+
+		if err := removal.Error(address); err != nil {
+			diags.AddError(
+				fmt.Sprintf("Removing host %s from the cluster failed", address),
+				fmt.Sprintf("Done before the failure: %s; %s; %s", steps, lpLog.Redact(err.Error()), lpLog.String()),
+			)
+			ok = false
+
+			continue
+		}
+
+		diags.AddWarning(
+			fmt.Sprintf("Host %s was removed from the cluster", address),
+			steps,
+		)
+	}
+
+	return ok
+}
+
+// verifyHostKeys verify the ssh host keys before launchpad connects to the hosts, and pin them for launchpad.
+//
+// Every host which fails gets an error in diags, and false is returned if any did.
+func (r *LaunchpadConfigResource) verifyHostKeys(cc *mcc_mke_api.ClusterConfig, diags *diag.Diagnostics) bool {
+	knownHostsFile := ""
+	if r.providerModel != nil {
+		knownHostsFile = r.providerModel.KnownHostsFile.ValueString()
+	}
+
+	errs := verifySSHHostKeys(cc, knownHostsFile, 10*time.Second)
+	for _, h := range cc.Spec.Hosts {
+		if err, ok := errs[h.Address()]; ok {
+			diags.AddError(
+				fmt.Sprintf("SSH host key verification failed for %s", h.Address()),
+				fmt.Sprintf("%s. Launchpad did not connect to the host, check the host or bastion host_key, or the provider known_hosts_file.", err.Error()),
+			)
+		}
+	}
+	return len(errs) == 0
+}
+
+// applyConcurrency how many hosts launchpad should work on in parallel for the resource.
+func (r *LaunchpadConfigResource) applyConcurrency(ls launchpadModel) int {
+	return int(int64ValueOr(ls.ApplyConcurrency, int64(r.providerModel.Concurrency())))
+}
+
+func (r *LaunchpadConfigResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	schema14 := launchpadSchema14()
+
+	return map[int64]resource.StateUpgrader{
+		// 0 => 1: the original schema, which had no launchpad execution settings, to the current one
+		0: {
+			PriorSchema: &schema14,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var ls14 launchpadSchema14Model
+
+				resp.Diagnostics.Append(req.State.Get(ctx, &ls14)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, launchpadModelFromSchema14(ls14))...)
+			},
+		},
+	}
+}
+
 func (r *LaunchpadConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	data, err := launchpadImportData(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Launchpad config import failed",
+			fmt.Sprintf("The import ID must be a launchpad.yaml path, or the base64 encoded file contents prefixed with '%s': %s", ImportIDBase64Prefix, err.Error()),
+		)
+
+		return
+	}
+
+	c, err := mcc_mke.NewMKE(data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Launchpad config import failed",
+			fmt.Sprintf("The imported launchpad config is not valid: %s", err.Error()),
+		)
+
+		return
+	}
 
+	ils, diags := launchpadModelFromClusterConfig(ctx, c.ClusterConfig)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if diags := resp.State.Set(ctx, ils); diags != nil {
+		resp.Diagnostics.Append(diags...)
+	}
 }
@@ -1,8 +1,16 @@
 package provider
 
 import (
+	"context"
+	"encoding/base64"
+	"fmt"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tf_resource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -13,19 +21,47 @@ func TestAccLaunchpadConfigResource(t *testing.T) {
 		Steps: []resource.TestStep{
 			// Create and Read testing
 			{
-				Config: testAccLaunchpadConfigResourceConfig_minimal(),
+				Config: testAccLaunchpadConfigResourceConfig_minimal("3.6.4"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("launchpad_config.test", "skip_destroy", "false"),
+					resource.TestCheckResourceAttr("launchpad_config.test", "apply_concurrency", "20"),
+					resource.TestCheckResourceAttr("launchpad_config.test", "force", "false"),
+					resource.TestCheckResourceAttr("launchpad_config.test", "disable_cleanup", "false"),
 					resource.TestCheckResourceAttr("launchpad_config.test", "spec.host.0.hooks.0.apply.0.before.0", "ls -la"),
+					resource.TestCheckResourceAttr("launchpad_config.test", "spec.host.0.hooks.0.reset.0.before.0", "umount /mnt/data"),
+					resource.TestCheckResourceAttr("launchpad_config.test", "spec.host.0.ssh.0.port", "22"),
+					resource.TestCheckResourceAttr("launchpad_config.test", "spec.mke.config_data", "[scheduling_configuration]"),
+					resource.TestCheckResourceAttr("launchpad_config.test", "planned_actions.0", "install MCR 22.10 on 4 hosts"),
 				),
 			},
+			// Update testing, an MKE upgrade
+			{
+				Config: testAccLaunchpadConfigResourceConfig_minimal("3.7.1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("launchpad_config.test", "spec.mke.version", "3.7.1"),
+					resource.TestCheckResourceAttr("launchpad_config.test", "planned_actions.#", "1"),
+					resource.TestCheckResourceAttr("launchpad_config.test", "planned_actions.0", "upgrade MKE 3.6.4 → 3.7.1 on 1 manager"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "launchpad_config.test",
+				ImportState:       true,
+				ImportStateId:     ImportIDBase64Prefix + base64.StdEncoding.EncodeToString([]byte(testAccLaunchpadConfigResourceLaunchpadYaml_minimal())),
+				ImportStateVerify: true,
+				// rig moves the default winrm port to 5986 when https is used, and
+				// apply_concurrency and planned_actions are not part of the launchpad.yaml
+				ImportStateVerifyIgnore: []string{"spec.host.2.winrm.0.port", "apply_concurrency", "planned_actions"},
+			},
 		},
 	})
 }
 
-func testAccLaunchpadConfigResourceConfig_minimal() string {
-	return `
+func testAccLaunchpadConfigResourceConfig_minimal(mkeVersion string) string {
+	return fmt.Sprintf(`
 resource "launchpad_config" "test" {
+    apply_concurrency = 20
+
     metadata {
         name = "test"
     }
@@ -34,9 +70,10 @@ resource "launchpad_config" "test" {
             version = "22.10"
         }
         mke {
-            version        = "3.6.4"
+            version        = "%s"
             admin_password = "mypassword"
             install_flags  = ["--flag1", "--flag2" ]
+            config_data    = "[scheduling_configuration]"
         }
         msr {
             version = "2.9.4"
@@ -54,6 +91,9 @@ resource "launchpad_config" "test" {
                 apply {
                     before = [ "ls -la", "pwd" ]
                 }
+                reset {
+                    before = [ "umount /mnt/data" ]
+                }
             }
         }
 
@@ -85,5 +125,121 @@ resource "launchpad_config" "test" {
         }
     }
 }
+`, mkeVersion)
+}
+
+// testAccLaunchpadConfigResourceLaunchpadYaml_minimal launchpad.yaml equivalent of the minimal resource config, used for import.
+func testAccLaunchpadConfigResourceLaunchpadYaml_minimal() string {
+	return `
+apiVersion: launchpad.mirantis.com/mke/v1.4
+kind: mke+msr
+metadata:
+  name: test
+spec:
+  hosts:
+  - role: manager
+    ssh:
+      address: manager1.example.org
+      keyPath: ./key.pem
+      user: ubuntu
+    hooks:
+      apply:
+        before: [ "ls -la", "pwd" ]
+      reset:
+        before: [ "umount /mnt/data" ]
+  - role: worker
+    ssh:
+      address: worker1.example.org
+      keyPath: ./key.pem
+      user: ubuntu
+  - role: worker
+    winRM:
+      address: windowsworker1.example.org
+      user: ubuntu
+      password: my-win-password
+      useHTTPS: true
+  - role: msr
+    ssh:
+      address: msr1.example.org
+      keyPath: ./key.pem
+      user: ubuntu
+  mcr:
+    version: "22.10"
+    channel: stable
+    repoURL: https://repos.mirantis.com
+    installURLLinux: https://get.mirantis.com/
+    installURLWindows: https://get.mirantis.com/install.ps1
+  mke:
+    version: 3.7.1
+    adminUsername: admin
+    adminPassword: mypassword
+    installFlags: [ "--flag1", "--flag2" ]
+    configData: "[scheduling_configuration]"
+  msr:
+    version: 2.9.4
+    replicaIDs: admin
 `
 }
+
+func TestLaunchpadConfigResourceModifyPlanUnknown(t *testing.T) {
+	ctx := context.Background()
+	schema := launchpadSchema()
+
+	// a fully typed state, as the upgrade from the original schema gives one
+	sls := launchpadModelFromSchema14(launchpadSchema14Model{
+		Id:          types.StringValue("test"),
+		SkipDestroy: types.BoolValue(false),
+		Metadata:    launchpadSchema14ModelMetadata{Name: types.StringValue("test")},
+		Spec: launchpadSchema14ModelSpec{
+			MCR: launchpadSchema14ModelSpecMCR{Version: types.StringValue("23.0"), Channel: types.StringValue("stable")},
+			MKE: launchpadSchema14ModelSpecMKE{
+				AdminUsername: types.StringValue("admin"),
+				AdminPassword: types.StringValue("mypassword"),
+				Version:       types.StringValue("3.6.4"),
+				InstallFlags:  types.ListNull(types.StringType),
+				UpgradeFlags:  types.ListNull(types.StringType),
+			},
+			Hosts: []launchpadSchema14ModelSpecHost{{
+				Role: types.StringValue("manager"),
+				SSH: []launchpadSchema14ModelSpecHostSSH{{
+					Address: types.StringValue("manager1.example.org"),
+					KeyPath: types.StringValue("./key.pem"),
+					User:    types.StringValue("ubuntu"),
+					Port:    types.Int64Value(22),
+				}},
+			}},
+		},
+	})
+
+	// the only manager is replaced by one whose address is not known yet
+	pls := sls
+	pls.Spec.Hosts = []launchpadModelSpecHost{sls.Spec.Hosts[0]}
+	pls.Spec.Hosts[0].SSH = []launchpadModelSpecHostSSH{sls.Spec.Hosts[0].SSH[0]}
+	pls.Spec.Hosts[0].SSH[0].Address = types.StringUnknown()
+	pls.PlannedActions = types.ListUnknown(types.StringType)
+
+	state := tfsdk.State{Schema: schema, Raw: tftypes.NewValue(schema.Type().TerraformType(ctx), nil)}
+	plan := tfsdk.Plan{Schema: schema, Raw: tftypes.NewValue(schema.Type().TerraformType(ctx), nil)}
+	if diags := state.Set(ctx, sls); diags.HasError() {
+		t.Fatalf("could not build the state: %v", diags)
+	}
+	if diags := plan.Set(ctx, pls); diags.HasError() {
+		t.Fatalf("could not build the plan: %v", diags)
+	}
+
+	r := &LaunchpadConfigResource{}
+	resp := tf_resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, tf_resource.ModifyPlanRequest{State: state, Plan: plan}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("did not expect a plan with unknown values to be refused: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() > 0 {
+		t.Errorf("did not expect a preview for a plan with unknown values: %v", resp.Diagnostics)
+	}
+
+	var actions types.List
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("planned_actions"), &actions)...)
+	if !actions.IsUnknown() {
+		t.Errorf("expected the planned actions to be unknown, got %s", actions)
+	}
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// launchpadChange a class of cluster change, which decides what launchpad has to do.
+type launchpadChange string
+
+const (
+	// launchpadChangeInstall products which are not installed yet, with the product names (MCR, MKE, MSR) as details.
+	launchpadChangeInstall     launchpadChange = "install"
+	launchpadChangeMCRUpgrade  launchpadChange = "MCR upgrade"
+	launchpadChangeMKEUpgrade  launchpadChange = "MKE upgrade"
+	launchpadChangeMSRUpgrade  launchpadChange = "MSR upgrade"
+	launchpadChangeMCRConfig   launchpadChange = "MCR config"
+	launchpadChangeHostAdded   launchpadChange = "host added"
+	launchpadChangeHostRemoved launchpadChange = "host removed"
+	launchpadChangeHook        launchpadChange = "hook only"
+	launchpadChangeCredential  launchpadChange = "credential only"
+	// launchpadChangeOther any change which has no narrower class, and so needs a full launchpad apply.
+	launchpadChangeOther launchpadChange = "other"
+)
+
+// launchpadChangeOrder the order in which change classes are reported.
+var launchpadChangeOrder = []launchpadChange{
+	launchpadChangeInstall,
+	launchpadChangeMCRUpgrade,
+	launchpadChangeMKEUpgrade,
+	launchpadChangeMSRUpgrade,
+	launchpadChangeMCRConfig,
+	launchpadChangeHostAdded,
+	launchpadChangeHostRemoved,
+	launchpadChangeHook,
+	launchpadChangeCredential,
+	launchpadChangeOther,
+}
+
+// launchpadDiff the classified changes between two cluster specs.
+type launchpadDiff struct {
+	changes map[launchpadChange][]string
+}
+
+func newLaunchpadDiff() launchpadDiff {
+	return launchpadDiff{changes: map[launchpadChange][]string{}}
+}
+
+// Add record a change, with the details (such as host addresses or field names) which it was found for.
+func (d launchpadDiff) Add(c launchpadChange, details ...string) {
+	d.changes[c] = append(d.changes[c], details...)
+}
+
+// Has was a change of the class found.
+func (d launchpadDiff) Has(c launchpadChange) bool {
+	_, ok := d.changes[c]
+	return ok
+}
+
+// Details the details that were recorded for a change class.
+func (d launchpadDiff) Details(c launchpadChange) []string {
+	return d.changes[c]
+}
+
+// Empty were no changes found at all.
+func (d launchpadDiff) Empty() bool {
+	return len(d.changes) == 0
+}
+
+// NeedsApply does launchpad have to run for the changes.
+//
+// Hooks only run as part of an apply, and connection credentials are only used to reach
+// the hosts, so changing only those does not change anything on the cluster.
+func (d launchpadDiff) NeedsApply() bool {
+	for c := range d.changes {
+		if c != launchpadChangeHook && c != launchpadChangeCredential {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsFullApply do the changes need every launchpad apply phase, rather than just the upgrade phases.
+func (d launchpadDiff) NeedsFullApply() bool {
+	return d.Has(launchpadChangeInstall) || d.Has(launchpadChangeHostAdded) || d.Has(launchpadChangeHostRemoved) || d.Has(launchpadChangeOther)
+}
+
+// String a human readable summary of the changes, e.g. "MKE upgrade, host added (worker2.example.org)".
+func (d launchpadDiff) String() string {
+	parts := []string{}
+	for _, c := range launchpadChangeOrder {
+		details, ok := d.changes[c]
+		if !ok {
+			continue
+		}
+		if len(details) == 0 {
+			parts = append(parts, string(c))
+			continue
+		}
+
+		parts = append(parts, fmt.Sprintf("%s (%s)", c, strings.Join(launchpadSorted(details), ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,281 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	mcc_mke_phase "github.com/Mirantis/mcc/pkg/product/mke/phase"
+)
+
+// testLaunchpadDiffModel a small cluster state to diff against.
+func testLaunchpadDiffModel() launchpadModel {
+	host := func(role, address string) launchpadModelSpecHost {
+		return launchpadModelSpecHost{
+			Role:  types.StringValue(role),
+			Hooks: []launchpadModelSpecHostHooks{},
+			SSH: []launchpadModelSpecHostSSH{{
+				Address: types.StringValue(address),
+				KeyPath: types.StringValue("./key.pem"),
+				User:    types.StringValue("ubuntu"),
+				Port:    types.Int64Value(22),
+			}},
+			WinRM: []launchpadModelSpecHostWinrm{},
+		}
+	}
+
+	return launchpadModel{
+		Spec: launchpadModelSpec{
+			MCR: launchpadModelSpecMCR{Version: types.StringValue("23.0"), Channel: types.StringValue("stable")},
+			MKE: launchpadModelSpecMKE{
+				Version:       types.StringValue("3.6.4"),
+				AdminUsername: types.StringValue("admin"),
+				AdminPassword: types.StringValue("mypassword"),
+				ImageRepo:     types.StringValue("docker.io/mirantis"),
+			},
+			MSR: []launchpadModelSpecMSR{{Version: types.StringValue("2.9.4")}},
+			Hosts: []launchpadModelSpecHost{
+				host("manager", "manager1.example.org"),
+				host("worker", "worker1.example.org"),
+				host("msr", "msr1.example.org"),
+			},
+		},
+	}
+}
+
+func TestLaunchpadModelDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		prior    func(ls *launchpadModel)
+		change   func(ls *launchpadModel)
+		expected []launchpadChange
+		apply    bool
+		full     bool
+	}{
+		{
+			name:   "no change",
+			change: func(ls *launchpadModel) {},
+		},
+		{
+			name: "host reorder",
+			change: func(ls *launchpadModel) {
+				ls.Spec.Hosts[0], ls.Spec.Hosts[1] = ls.Spec.Hosts[1], ls.Spec.Hosts[0]
+			},
+		},
+		{
+			name: "mcr upgrade",
+			change: func(ls *launchpadModel) {
+				ls.Spec.MCR.Version = types.StringValue("23.0.1")
+			},
+			expected: []launchpadChange{launchpadChangeMCRUpgrade},
+			apply:    true,
+		},
+		{
+			name: "mke and msr not installed",
+			prior: func(ls *launchpadModel) {
+				ls.Spec.MKE.Version = types.StringValue("")
+				ls.Spec.MSR[0].Version = types.StringValue("")
+			},
+			change:   func(ls *launchpadModel) {},
+			expected: []launchpadChange{launchpadChangeInstall},
+			apply:    true,
+			full:     true,
+		},
+		{
+			name: "mke and msr upgrade",
+			change: func(ls *launchpadModel) {
+				ls.Spec.MKE.Version = types.StringValue("3.7.1")
+				ls.Spec.MSR[0].Version = types.StringValue("2.9.5")
+			},
+			expected: []launchpadChange{launchpadChangeMKEUpgrade, launchpadChangeMSRUpgrade},
+			apply:    true,
+		},
+		{
+			name: "mcr daemon config",
+			change: func(ls *launchpadModel) {
+				ls.Spec.MCR.DaemonConfig = types.StringValue(`{"log-driver":"journald"}`)
+			},
+			expected: []launchpadChange{launchpadChangeMCRConfig},
+			apply:    true,
+		},
+		{
+			name: "host daemon config",
+			change: func(ls *launchpadModel) {
+				ls.Spec.Hosts[1].DaemonConfig = types.StringValue(`{"registry-mirrors":["https://mirror.example.org"]}`)
+			},
+			expected: []launchpadChange{launchpadChangeMCRConfig},
+			apply:    true,
+		},
+		{
+			name: "airgap",
+			change: func(ls *launchpadModel) {
+				ls.Spec.Airgap = []launchpadModelSpecAirgap{{ImageBundle: types.StringValue("./mke_images.tar.gz")}}
+			},
+			expected: []launchpadChange{launchpadChangeOther},
+			apply:    true,
+			full:     true,
+		},
+		{
+			name: "host daemon config removed",
+			prior: func(ls *launchpadModel) {
+				ls.Spec.MCR.DaemonConfig = types.StringValue(`{"log-driver":"journald"}`)
+				ls.Spec.Hosts[1].DaemonConfig = types.StringValue(`{"debug":true}`)
+			},
+			change: func(ls *launchpadModel) {
+				ls.Spec.MCR.DaemonConfig = types.StringValue(`{"log-driver":"journald"}`)
+			},
+		},
+		{
+			name: "host added",
+			change: func(ls *launchpadModel) {
+				h := ls.Spec.Hosts[1]
+				h.SSH = []launchpadModelSpecHostSSH{h.SSH[0]}
+				h.SSH[0].Address = types.StringValue("worker2.example.org")
+				ls.Spec.Hosts = append(ls.Spec.Hosts, h)
+			},
+			expected: []launchpadChange{launchpadChangeHostAdded},
+			apply:    true,
+			full:     true,
+		},
+		{
+			name: "host removed",
+			change: func(ls *launchpadModel) {
+				ls.Spec.Hosts = ls.Spec.Hosts[:2]
+			},
+			expected: []launchpadChange{launchpadChangeHostRemoved},
+			apply:    true,
+			full:     true,
+		},
+		{
+			name: "hook only",
+			change: func(ls *launchpadModel) {
+				ls.Spec.Hosts[0].Hooks = []launchpadModelSpecHostHooks{{
+					Apply: []launchpadModelSpecHostHookAction{{
+						Before: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ls -la")}),
+						After:  types.ListNull(types.StringType),
+					}},
+				}}
+			},
+			expected: []launchpadChange{launchpadChangeHook},
+		},
+		{
+			name: "credential only",
+			change: func(ls *launchpadModel) {
+				ssh := ls.Spec.Hosts[0].SSH[0]
+				ssh.KeyPath = types.StringValue("./other.pem")
+				ls.Spec.Hosts[0].SSH = []launchpadModelSpecHostSSH{ssh}
+				ls.Spec.MKE.AdminPassword = types.StringValue("otherpassword")
+			},
+			expected: []launchpadChange{launchpadChangeCredential},
+		},
+		{
+			name: "other",
+			change: func(ls *launchpadModel) {
+				ls.Spec.MKE.ImageRepo = types.StringValue("registry.example.org/mirantis")
+			},
+			expected: []launchpadChange{launchpadChangeOther},
+			apply:    true,
+			full:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sls := testLaunchpadDiffModel()
+			pls := testLaunchpadDiffModel()
+			if test.prior != nil {
+				test.prior(&sls)
+			}
+			test.change(&pls)
+
+			d := sls.Diff(pls)
+
+			for _, c := range launchpadChangeOrder {
+				expected := false
+				for _, e := range test.expected {
+					expected = expected || e == c
+				}
+				if d.Has(c) != expected {
+					t.Errorf("change %q: expected %t, got %t (%s)", c, expected, d.Has(c), d)
+				}
+			}
+			if d.NeedsApply() != test.apply {
+				t.Errorf("expected NeedsApply %t (%s)", test.apply, d)
+			}
+			if d.NeedsFullApply() != test.full {
+				t.Errorf("expected NeedsFullApply %t (%s)", test.full, d)
+			}
+		})
+	}
+}
+
+func TestLaunchpadApplyPhases(t *testing.T) {
+	d := newLaunchpadDiff()
+	d.Add(launchpadChangeHook, "manager1.example.org")
+	if phases := launchpadApplyPhases(d, false, false, 10); len(phases) != 0 {
+		t.Errorf("expected no phases for a hook change, got %d", len(phases))
+	}
+
+	d.Add(launchpadChangeMKEUpgrade, "3.6.4 → 3.7.1")
+	titles := map[string]bool{}
+	for _, p := range launchpadApplyPhases(d, false, false, 10) {
+		titles[p.Title()] = true
+	}
+	for _, title := range []string{"Upgrade MKE components", "Open Remote Connection", "Upload images"} {
+		if !titles[title] {
+			t.Errorf("expected phase %q for an MKE upgrade, got %v", title, titles)
+		}
+	}
+	for _, title := range []string{"Upgrade Mirantis Container Runtime on the hosts", "Join managers to swarm"} {
+		if titles[title] {
+			t.Errorf("did not expect phase %q for an MKE upgrade", title)
+		}
+	}
+
+	d.Add(launchpadChangeHostAdded, "worker2.example.org")
+	if phases := launchpadApplyPhases(d, false, false, 10); len(phases) < 30 {
+		t.Errorf("expected the full apply phases for a new host, got %d", len(phases))
+	}
+}
+
+func TestLaunchpadApplyPhasesMCRConfig(t *testing.T) {
+	d := newLaunchpadDiff()
+	d.Add(launchpadChangeMCRConfig, "worker1.example.org")
+	titles := []string{}
+	for _, p := range launchpadApplyPhases(d, false, false, 10) {
+		titles = append(titles, p.Title())
+	}
+
+	configure, restart := -1, -1
+	for i, title := range titles {
+		switch title {
+		case "Configure Mirantis Container Runtime on the hosts":
+			configure = i
+		case "Restart Mirantis Container Runtime on the hosts":
+			restart = i
+		case "Upgrade Mirantis Container Runtime on the hosts", "Install Mirantis Container Runtime on the hosts":
+			t.Errorf("did not expect phase %q for an MCR config change", title)
+		}
+	}
+	if configure < 0 || restart < configure {
+		t.Errorf("expected MCR to be configured and then restarted, got %v", titles)
+	}
+}
+
+func TestLaunchpadApplyPhasesAirgap(t *testing.T) {
+	d := newLaunchpadDiff()
+	d.Add(launchpadChangeInstall, "MCR", "MKE", "MSR")
+
+	for _, airgap := range []bool{false, true} {
+		check := false
+		for _, p := range launchpadApplyPhases(d, false, airgap, 10) {
+			if _, ok := p.(*mcc_mke_phase.UpgradeCheck); ok {
+				check = true
+			}
+		}
+		if check == airgap {
+			t.Errorf("expected the upgrade check %t for airgap %t", !airgap, airgap)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"sync"
+
+	mcc_phase "github.com/Mirantis/mcc/pkg/phase"
+	mcc_common_phase "github.com/Mirantis/mcc/pkg/product/common/phase"
+	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
+	mcc_mke_phase "github.com/Mirantis/mcc/pkg/product/mke/phase"
+	mcc_swarm "github.com/Mirantis/mcc/pkg/swarm"
+)
+
+// launchpadDiscovery inspects the hosts of an existing cluster, using the mcc gather phases.
+//
+// The discovery itself runs as an mcc phase, so that it can use the host connections
+// before they are closed.
+type launchpadDiscovery struct {
+	mcc_phase.BasicPhase
+
+	swarmMembers map[*mcc_mke_api.Host]bool
+	mu           sync.Mutex
+}
+
+// discoverCluster connect to the cluster hosts and gather what is installed on them.
+func discoverCluster(cc *mcc_mke_api.ClusterConfig) (*launchpadDiscovery, error) {
+	d := &launchpadDiscovery{
+		swarmMembers: map[*mcc_mke_api.Host]bool{},
+	}
+
+	phaseManager := mcc_phase.NewManager(cc)
+	phaseManager.AddPhases(
+		&mcc_common_phase.Connect{},
+		&mcc_mke_phase.DetectOS{},
+		&mcc_mke_phase.GatherFacts{},
+		d,
+		&mcc_common_phase.Disconnect{},
+	)
+
+	if err := phaseManager.Run(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Title for the phase.
+func (d *launchpadDiscovery) Title() string {
+	return "Discover Swarm Membership"
+}
+
+// Run check which of the hosts are currently part of the swarm.
+func (d *launchpadDiscovery) Run() error {
+	return d.Config.Spec.Hosts.ParallelEach(func(h *mcc_mke_api.Host) error {
+		member := false
+		if h.Metadata != nil && h.Metadata.MCRVersion != "" {
+			member = mcc_swarm.IsSwarmNode(h)
+		}
+
+		d.mu.Lock()
+		d.swarmMembers[h] = member
+		d.mu.Unlock()
+
+		return nil
+	})
+}
+
+// IsSwarmMember was the host found to be part of the swarm.
+func (d *launchpadDiscovery) IsSwarmMember(h *mcc_mke_api.Host) bool {
+	return d.swarmMembers[h]
+}
+
+// Installed is there anything left of the cluster on the hosts.
+func (d *launchpadDiscovery) Installed() bool {
+	if d.Config.Spec.MKE.Metadata != nil && d.Config.Spec.MKE.Metadata.Installed {
+		return true
+	}
+
+	for _, h := range d.Config.Spec.Hosts {
+		if h.Metadata != nil && h.Metadata.MCRVersion != "" {
+			return true
+		}
+	}
+
+	return false
+}
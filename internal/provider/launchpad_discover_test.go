@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
+)
+
+func TestLaunchpadModelRefresh(t *testing.T) {
+	ls := launchpadModel{
+		Spec: launchpadModelSpec{
+			MCR: launchpadModelSpecMCR{Version: types.StringValue("23.0")},
+			MKE: launchpadModelSpecMKE{Version: types.StringValue("3.7.1")},
+			MSR: []launchpadModelSpecMSR{{Version: types.StringValue("2.9.4")}},
+			Hosts: []launchpadModelSpecHost{
+				{Role: types.StringValue("manager")},
+				{Role: types.StringValue("worker")},
+				{Role: types.StringValue("msr")},
+			},
+		},
+	}
+
+	cc, err := ls.ClusterConfig(&diag.Diagnostics{})
+	if err != nil {
+		t.Fatalf("unexpected cluster config error: %s", err)
+	}
+
+	hosts := cc.Spec.Hosts
+	hosts[0].Metadata = &mcc_mke_api.HostMetadata{MCRVersion: "23.0.3"}
+	hosts[1].Metadata = &mcc_mke_api.HostMetadata{MCRVersion: "20.10.13"}
+	hosts[2].Metadata = &mcc_mke_api.HostMetadata{MCRVersion: "23.0.3"}
+	hosts[2].MSRMetadata = &mcc_mke_api.MSRMetadata{Installed: true, InstalledVersion: "2.9.4"}
+	cc.Spec.MKE.Metadata = &mcc_mke_api.MKEMetadata{Installed: true, InstalledVersion: "3.6.4"}
+
+	d := &launchpadDiscovery{
+		swarmMembers: map[*mcc_mke_api.Host]bool{
+			hosts[0]: true,
+			hosts[1]: false,
+			hosts[2]: true,
+		},
+	}
+	d.Config = &cc
+
+	if !d.Installed() {
+		t.Fatal("expected the discovered cluster to be installed")
+	}
+
+	ls.Refresh(d)
+
+	if len(ls.Spec.Hosts) != 2 {
+		t.Errorf("expected the worker which left the swarm to be dropped, got %d hosts", len(ls.Spec.Hosts))
+	}
+	if v := ls.Spec.MCR.Version.ValueString(); v != "23.0" {
+		t.Errorf("MCR version prefix should match the installed version, got %s", v)
+	}
+	if v := ls.Spec.MKE.Version.ValueString(); v != "3.6.4" {
+		t.Errorf("expected MKE drift to the installed version, got %s", v)
+	}
+	if v := ls.Spec.MSR[0].Version.ValueString(); v != "2.9.4" {
+		t.Errorf("expected MSR version to be unchanged, got %s", v)
+	}
+}
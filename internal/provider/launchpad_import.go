@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// ImportIDBase64Prefix marks an import ID as an inline base64 encoded launchpad.yaml, instead of a file path.
+	ImportIDBase64Prefix = "base64:"
+)
+
+// launchpadImportData retrieve the launchpad.yaml contents for an import ID.
+//
+// The ID is either a path to a launchpad.yaml file, or the base64 encoded file
+// contents prefixed with "base64:".
+func launchpadImportData(id string) ([]byte, error) {
+	if strings.HasPrefix(id, ImportIDBase64Prefix) {
+		data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(id, ImportIDBase64Prefix))
+		if err != nil {
+			return nil, fmt.Errorf("could not decode inline launchpad config: %w", err)
+		}
+		return data, nil
+	}
+
+	data, err := os.ReadFile(id)
+	if err != nil {
+		return nil, fmt.Errorf("could not read launchpad config file: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
+	k0s_rig_log "github.com/k0sproject/rig/log"
+	mcc_logrus "github.com/sirupsen/logrus"
+)
+
+var (
+	// launchpadLogANSI matches the terminal colour codes that launchpad puts into some messages.
+	launchpadLogANSI = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	// launchpadLogHost matches the host prefix that launchpad puts on host messages, e.g. "[ssh] 10.0.0.1:22: ".
+	launchpadLogHost = regexp.MustCompile(`^(\[(?:ssh|winrm|local)\] ([^ ]+?)): `)
+	// launchpadLogPhase matches the message that launchpad logs when it starts a phase.
+	launchpadLogPhase = regexp.MustCompile(`^==> Running phase: (.+)$`)
+
+	// launchpadLogs routes the global launchpad log to the captures of the running resource operations.
+	launchpadLogs     = &launchpadLogRouter{captures: map[*launchpadLog]bool{}}
+	launchpadLogsOnce sync.Once
+)
+
+// launchpadLogRouter is the single logrus hook for the launchpad (mcc) log.
+//
+// Launchpad logs through the global logrus logger, so when terraform runs operations for
+// several resources in parallel, their log entries arrive on the same logger. Entries which
+// name a host are routed to the operations which manage that host. Entries which do not
+// (such as phase titles) cannot be told apart, so they are only captured while a single
+// operation runs, and dropped while there are several, rather than mixing up their logs
+// and leaking secrets which only the other operations redact.
+type launchpadLogRouter struct {
+	captures map[*launchpadLog]bool
+	mu       sync.Mutex
+}
+
+// Levels all log levels are routed.
+func (r *launchpadLogRouter) Levels() []mcc_logrus.Level {
+	return mcc_logrus.AllLevels
+}
+
+// Fire route a single launchpad log entry.
+func (r *launchpadLogRouter) Fire(entry *mcc_logrus.Entry) error {
+	line, err := entry.String()
+	if err != nil {
+		return err
+	}
+
+	msg := launchpadLogANSI.ReplaceAllString(entry.Message, "")
+	host, address := "", ""
+	if m := launchpadLogHost.FindStringSubmatch(msg); m != nil {
+		host, msg = m[1], strings.TrimPrefix(msg, m[0])
+		address = m[2]
+		if a, _, err := net.SplitHostPort(address); err == nil {
+			address = a
+		}
+	}
+
+	r.mu.Lock()
+	targets := []*launchpadLog{}
+	for l := range r.captures {
+		if address != "" && l.hosts[address] {
+			targets = append(targets, l)
+		}
+	}
+	if address == "" && len(r.captures) == 1 {
+		for l := range r.captures {
+			targets = append(targets, l)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, l := range targets {
+		if err := l.capture(entry, line, msg, host); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *launchpadLogRouter) add(l *launchpadLog) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.captures[l] = true
+}
+
+func (r *launchpadLogRouter) remove(l *launchpadLog) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.captures, l)
+}
+
+// launchpadLog captures the launchpad (mcc) log output for a single resource operation.
+//
+// Every routed logrus entry is forwarded to tflog, so that TF_LOG shows launchpad progress
+// live, is kept in a buffer so that it can be added to diagnostics, and is optionally
+// appended to a log file. Known secrets are scrubbed from everything that is captured.
+type launchpadLog struct {
+	ctx     context.Context
+	buffer  bytes.Buffer
+	file    *os.File
+	phase   string
+	hosts   map[string]bool
+	secrets []string
+	mu      sync.Mutex
+}
+
+// startLaunchpadLog start capturing the launchpad logs for a cluster, optionally also into a log file.
+func startLaunchpadLog(ctx context.Context, logFile string, cc mcc_mke_api.ClusterConfig) (*launchpadLog, error) {
+	l := &launchpadLog{
+		ctx:     ctx,
+		hosts:   map[string]bool{},
+		secrets: clusterConfigSecrets(cc),
+	}
+
+	if cc.Spec != nil {
+		for _, h := range cc.Spec.Hosts {
+			l.hosts[h.Address()] = true
+		}
+	}
+
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("could not open launchpad log file: %w", err)
+		}
+		l.file = f
+	}
+
+	launchpadLogsOnce.Do(func() {
+		// rig logs on its own unless it is given a logger
+		k0s_rig_log.Log = mcc_logrus.StandardLogger()
+
+		mcc_logrus.SetOutput(io.Discard)
+		mcc_logrus.AddHook(launchpadLogs)
+	})
+	launchpadLogs.add(l)
+
+	return l, nil
+}
+
+// Stop capturing launchpad logs.
+func (l *launchpadLog) Stop() {
+	launchpadLogs.remove(l)
+
+	if l.file != nil {
+		if err := l.file.Close(); err != nil {
+			tflog.Warn(l.ctx, "could not close launchpad log file", map[string]interface{}{"error": err.Error()})
+		}
+	}
+}
+
+// Redact scrub the known secrets from a string, such as an error message.
+func (l *launchpadLog) Redact(s string) string {
+	return redactSecrets(s, l.secrets)
+}
+
+// String the captured launchpad log output.
+func (l *launchpadLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.buffer.String()
+}
+
+// capture a single launchpad log entry, which the router already formatted and parsed.
+func (l *launchpadLog) capture(entry *mcc_logrus.Entry, line, msg, host string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	line = l.Redact(line)
+	l.buffer.WriteString(line)
+	if l.file != nil {
+		if _, err := l.file.WriteString(line); err != nil {
+			return err
+		}
+	}
+
+	msg = l.Redact(msg)
+	fields := map[string]interface{}{}
+	for k, v := range entry.Data {
+		fields[k] = l.Redact(fmt.Sprint(v))
+	}
+
+	if m := launchpadLogPhase.FindStringSubmatch(msg); m != nil {
+		l.phase = strings.TrimSpace(m[1])
+	}
+	if l.phase != "" {
+		fields["phase"] = l.phase
+	}
+	if host != "" {
+		fields["host"] = host
+	}
+
+	switch entry.Level {
+	case mcc_logrus.TraceLevel:
+		tflog.Trace(l.ctx, msg, fields)
+	case mcc_logrus.DebugLevel:
+		tflog.Debug(l.ctx, msg, fields)
+	case mcc_logrus.InfoLevel:
+		tflog.Info(l.ctx, msg, fields)
+	case mcc_logrus.WarnLevel:
+		tflog.Warn(l.ctx, msg, fields)
+	default:
+		tflog.Error(l.ctx, msg, fields)
+	}
+
+	return nil
+}
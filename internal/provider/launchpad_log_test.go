@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
+	k0s_rig "github.com/k0sproject/rig"
+	mcc_logrus "github.com/sirupsen/logrus"
+)
+
+// testLaunchpadLogClusterConfig a minimal cluster with ssh hosts on the addresses.
+func testLaunchpadLogClusterConfig(password string, addresses ...string) mcc_mke_api.ClusterConfig {
+	spec := &mcc_mke_api.ClusterSpec{MKE: mcc_mke_api.MKEConfig{AdminPassword: password}}
+	for _, a := range addresses {
+		spec.Hosts = append(spec.Hosts, &mcc_mke_api.Host{
+			Connection: k0s_rig.Connection{SSH: &k0s_rig.SSH{Address: a, Port: 22}},
+		})
+	}
+	return mcc_mke_api.ClusterConfig{Spec: spec}
+}
+
+func TestLaunchpadLogCapture(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "launchpad.log")
+
+	lpLog, err := startLaunchpadLog(context.Background(), logFile, testLaunchpadLogClusterConfig("mypassword", "manager1.example.org"))
+	if err != nil {
+		t.Fatalf("could not start log capture: %s", err)
+	}
+
+	// launchpad colours the phase messages
+	mcc_logrus.Infof("\x1b[32m==> Running phase: %s\x1b[0m", "Gather Facts")
+	mcc_logrus.Infof("%s: gathering host facts", "[ssh] manager1.example.org:22")
+	mcc_logrus.Debugf("%s: executing docker login -p mypassword", "[ssh] manager1.example.org:22")
+	lpLog.Stop()
+
+	mcc_logrus.Info("logged after the capture was stopped")
+
+	if lpLog.phase != "Gather Facts" {
+		t.Errorf("expected the phase to be tracked, got %q", lpLog.phase)
+	}
+
+	captured := lpLog.String()
+	if !strings.Contains(captured, "gathering host facts") {
+		t.Errorf("expected host message to be captured: %s", captured)
+	}
+	if strings.Contains(captured, "mypassword") {
+		t.Errorf("expected secrets to be scrubbed: %s", captured)
+	}
+	if strings.Contains(captured, "after the capture was stopped") {
+		t.Errorf("expected no capture after stop: %s", captured)
+	}
+
+	written, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("could not read log file: %s", err)
+	}
+	if string(written) != captured {
+		t.Errorf("log file does not match the captured log: %s", written)
+	}
+}
+
+func TestLaunchpadLogConcurrentCapture(t *testing.T) {
+	first, err := startLaunchpadLog(context.Background(), "", testLaunchpadLogClusterConfig("firstpassword", "10.0.0.1"))
+	if err != nil {
+		t.Fatalf("could not start first log capture: %s", err)
+	}
+	second, err := startLaunchpadLog(context.Background(), "", testLaunchpadLogClusterConfig("secondpassword", "10.0.0.2"))
+	if err != nil {
+		t.Fatalf("could not start second log capture: %s", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, a := range []string{"10.0.0.1", "10.0.0.2"} {
+		wg.Add(1)
+		go func(a string) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				mcc_logrus.Infof("[ssh] %s:22: message %d from %s", a, i, a)
+			}
+		}(a)
+	}
+	wg.Wait()
+	mcc_logrus.Info("==> Running phase: Disconnect")
+	mcc_logrus.Info("cluster message with secondpassword")
+
+	first.Stop()
+	second.Stop()
+
+	for l, hosts := range map[*launchpadLog][2]string{first: {"10.0.0.1", "10.0.0.2"}, second: {"10.0.0.2", "10.0.0.1"}} {
+		captured := l.String()
+		if c := strings.Count(captured, "from "+hosts[0]); c != 50 {
+			t.Errorf("expected 50 messages for %s, got %d", hosts[0], c)
+		}
+		if strings.Contains(captured, "from "+hosts[1]) {
+			t.Errorf("expected no messages from the other cluster host %s: %s", hosts[1], captured)
+		}
+		if l.phase != "" || strings.Contains(captured, "cluster message") {
+			t.Errorf("expected messages without a host to be dropped while several captures run: %s", captured)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	k0s_dig "github.com/k0sproject/dig"
+)
+
+// launchpadDaemonConfigCheck refuse MCR daemon configs which are not JSON objects, and return the path of the daemon_config attribute.
+func launchpadDaemonConfigCheck(pls launchpadModel) (path.Path, error) {
+	if _, err := (launchpadModelSpecHost{}).MCRDaemonConfig(pls.Spec.MCR); err != nil {
+		return path.Root("spec").AtName("mcr").AtName("daemon_config"), err
+	}
+	for i, h := range pls.Spec.Hosts {
+		if _, err := h.MCRDaemonConfig(launchpadModelSpecMCR{}); err != nil {
+			return launchpadHostPath(i).AtName("daemon_config"), fmt.Errorf("host %s: %w", h.Address(), err)
+		}
+	}
+	return path.Empty(), nil
+}
+
+// MCRDaemonConfig the MCR daemon.json for the host, which is its daemon_config merged over the spec.mcr daemon_config.
+func (h launchpadModelSpecHost) MCRDaemonConfig(mcr launchpadModelSpecMCR) (k0s_dig.Mapping, error) {
+	dc := k0s_dig.Mapping{}
+	for _, c := range []struct {
+		name   string
+		config types.String
+	}{
+		{name: "spec.mcr daemon_config", config: mcr.DaemonConfig},
+		{name: "daemon_config", config: h.DaemonConfig},
+	} {
+		if c.config.ValueString() == "" {
+			continue
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(c.config.ValueString()), &m); err != nil {
+			return dc, fmt.Errorf("%s is not a JSON object: %w", c.name, err)
+		}
+		for k, v := range m {
+			dc[k] = v
+		}
+	}
+	return dc, nil
+}
+
+// launchpadDaemonConfigChanged does the planned MCR daemon config set any key which the prior one did not set to the same value.
+//
+// Launchpad adds the keys of the existing daemon.json which are not set, so removing keys,
+// or the whole daemon_config, does not change the daemon.json on the host.
+func launchpadDaemonConfigChanged(prior, planned k0s_dig.Mapping) bool {
+	for k, v := range planned {
+		if pv, ok := prior[k]; !ok || !reflect.DeepEqual(pv, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestLaunchpadDaemonConfigCheck(t *testing.T) {
+	pls := testLaunchpadDiffModel()
+	pls.Spec.MCR.DaemonConfig = types.StringValue(`{"log-driver":"journald","debug":false}`)
+	pls.Spec.Hosts[0].DaemonConfig = types.StringValue(`{"debug":true}`)
+	if _, err := launchpadDaemonConfigCheck(pls); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	dc, _ := pls.Spec.Hosts[0].MCRDaemonConfig(pls.Spec.MCR)
+	if dc["log-driver"] != "journald" || dc["debug"] != true {
+		t.Errorf("expected the host daemon config merged over the default, got %v", dc)
+	}
+
+	pls.Spec.Hosts[1].DaemonConfig = types.StringValue(`["not", "an", "object"]`)
+	if p, err := launchpadDaemonConfigCheck(pls); err == nil {
+		t.Error("expected an error for a daemon config which is not an object")
+	} else if expected := path.Root("spec").AtName("host").AtListIndex(1).AtName("daemon_config"); !p.Equal(expected) {
+		t.Errorf("expected the error on %s, got %s", expected, p)
+	}
+
+	pls.Spec.MCR.DaemonConfig = types.StringValue(`{"debug":`)
+	if p, err := launchpadDaemonConfigCheck(pls); err == nil {
+		t.Error("expected an error for a spec.mcr daemon config which is not JSON")
+	} else if expected := path.Root("spec").AtName("mcr").AtName("daemon_config"); !p.Equal(expected) {
+		t.Errorf("expected the error on %s, got %s", expected, p)
+	}
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	mcc_common_api "github.com/Mirantis/mcc/pkg/product/common/api"
+)
+
+// launchpadCloudProviderCheck refuse a cloud provider config for providers which launchpad cannot write a config for.
+func launchpadCloudProviderCheck(pls launchpadModel) error {
+	if len(pls.Spec.MKE.CloudProvider) == 0 {
+		return nil
+	}
+
+	cp := pls.Spec.MKE.CloudProvider[0]
+	if cp.Provider.IsUnknown() || (cp.ConfigFile.IsNull() && cp.ConfigData.IsNull()) {
+		return nil
+	}
+	for _, p := range MKECloudConfigProviders {
+		if cp.Provider.ValueString() == p {
+			return nil
+		}
+	}
+	return fmt.Errorf("a cloud provider config is only supported for the %s cloud providers, not %s", strings.Join(MKECloudConfigProviders, " and "), cp.Provider.ValueString())
+}
+
+// launchpadCloudProviderChanged does the cloud provider change for an MKE which is already installed.
+//
+// Launchpad only sets up the cloud provider when it installs MKE.
+func launchpadCloudProviderChanged(sls, pls launchpadModel) bool {
+	return !reflect.DeepEqual(sls.Spec.MKE.CloudProvider, pls.Spec.MKE.CloudProvider)
+}
+
+// launchpadSwarmInstallFlagsCheck refuse swarm install flags which launchpad sets itself.
+func launchpadSwarmInstallFlagsCheck(pls launchpadModel) error {
+	if pls.Spec.MKE.SwarmInstallFlags.IsNull() || pls.Spec.MKE.SwarmInstallFlags.IsUnknown() {
+		return nil
+	}
+
+	for _, v := range pls.Spec.MKE.SwarmInstallFlags.Elements() {
+		f, ok := v.(types.String)
+		if !ok || f.IsUnknown() {
+			continue
+		}
+		if mcc_common_api.Flags([]string{f.ValueString()}).Include("--advertise-addr") {
+			return fmt.Errorf("--advertise-addr is set by launchpad, to the swarm leader address")
+		}
+	}
+	return nil
+}
+
+// launchpadSwarmChanged do the swarm install flags or update commands change for a swarm which is already initialized.
+//
+// Launchpad only uses them when it initializes the swarm.
+func launchpadSwarmChanged(sls, pls launchpadModel) bool {
+	return !sls.Spec.MKE.SwarmInstallFlags.Equal(pls.Spec.MKE.SwarmInstallFlags) || !sls.Spec.MKE.SwarmUpdateCommands.Equal(pls.Spec.MKE.SwarmUpdateCommands)
+}
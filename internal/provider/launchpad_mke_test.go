@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestLaunchpadCloudProviderCheck(t *testing.T) {
+	pls := testLaunchpadDiffModel()
+	if err := launchpadCloudProviderCheck(pls); err != nil {
+		t.Errorf("unexpected error without a cloud provider: %s", err)
+	}
+
+	pls.Spec.MKE.CloudProvider = []launchpadModelSpecMKECloudProvider{{
+		Provider:   types.StringValue("aws"),
+		ConfigFile: types.StringNull(),
+		ConfigData: types.StringNull(),
+	}}
+	if err := launchpadCloudProviderCheck(pls); err != nil {
+		t.Errorf("unexpected error for aws without a config: %s", err)
+	}
+
+	pls.Spec.MKE.CloudProvider[0].ConfigData = types.StringValue("[Global]")
+	if err := launchpadCloudProviderCheck(pls); err == nil {
+		t.Error("expected an error for an aws cloud config")
+	}
+
+	pls.Spec.MKE.CloudProvider[0].Provider = types.StringValue("openstack")
+	if err := launchpadCloudProviderCheck(pls); err != nil {
+		t.Errorf("unexpected error for an openstack cloud config: %s", err)
+	}
+
+	sls := testLaunchpadDiffModel()
+	if !launchpadCloudProviderChanged(sls, pls) {
+		t.Error("expected a cloud provider change")
+	}
+}
+
+func TestLaunchpadSwarmInstallFlagsCheck(t *testing.T) {
+	pls := testLaunchpadDiffModel()
+	pls.Spec.MKE.SwarmInstallFlags = testStringList("--data-path-port=7789", "--default-addr-pool 10.20.0.0/16")
+	if err := launchpadSwarmInstallFlagsCheck(pls); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	sls := testLaunchpadDiffModel()
+	sls.Spec.MKE.SwarmInstallFlags = types.ListNull(types.StringType)
+	if !launchpadSwarmChanged(sls, pls) {
+		t.Error("expected a swarm install flags change")
+	}
+
+	pls.Spec.MKE.SwarmInstallFlags = testStringList("--advertise-addr=10.0.0.1")
+	if err := launchpadSwarmInstallFlagsCheck(pls); err == nil {
+		t.Error("expected an error for --advertise-addr")
+	}
+}
@@ -0,0 +1,379 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type launchpadModel struct {
+	Id               types.String `tfsdk:"id"`
+	SkipDestroy      types.Bool   `tfsdk:"skip_destroy"`
+	ApplyConcurrency types.Int64  `tfsdk:"apply_concurrency"`
+	Force            types.Bool   `tfsdk:"force"`
+	DisableCleanup   types.Bool   `tfsdk:"disable_cleanup"`
+	LogFile          types.String `tfsdk:"log_file"`
+	PlannedActions   types.List   `tfsdk:"planned_actions"`
+
+	Metadata launchpadModelMetadata `tfsdk:"metadata"`
+	Spec     launchpadModelSpec     `tfsdk:"spec"`
+}
+
+// Diff classify the changes from this (prior) state to another (planned) state, to decide what launchpad has to do.
+//
+// Hosts are matched by their address, so reordering the host blocks is not a change. An
+// empty prior version is a product which Refresh found not installed, which needs an
+// install rather than an upgrade.
+func (ls launchpadModel) Diff(c launchpadModel) launchpadDiff {
+	d := newLaunchpadDiff()
+
+	// reset_removed_hosts only matters when hosts are removed, which is a change of its own
+	if ls.Prune() != c.Prune() {
+		d.Add(launchpadChangeOther, "cluster")
+	}
+
+	if ls.Airgap() != c.Airgap() || ls.AirgapImageBundle() != c.AirgapImageBundle() {
+		d.Add(launchpadChangeOther, "airgap")
+	}
+
+	lmcr, cmcr := ls.Spec.MCR, c.Spec.MCR
+	if lmcr.Version.ValueString() == "" && !cmcr.Version.Equal(lmcr.Version) {
+		d.Add(launchpadChangeInstall, "MCR")
+	} else if !lmcr.Version.Equal(cmcr.Version) {
+		d.Add(launchpadChangeMCRUpgrade, fmt.Sprintf("%s → %s", lmcr.Version.ValueString(), cmcr.Version.ValueString()))
+	}
+	lmcr.Version, cmcr.Version = types.String{}, types.String{}
+	lmcr.DaemonConfig, cmcr.DaemonConfig = types.String{}, types.String{}
+	if !reflect.DeepEqual(lmcr, cmcr) {
+		d.Add(launchpadChangeOther, "mcr")
+	}
+
+	lmke, cmke := ls.Spec.MKE, c.Spec.MKE
+	if lmke.Version.ValueString() == "" && !cmke.Version.Equal(lmke.Version) {
+		d.Add(launchpadChangeInstall, "MKE")
+	} else if !lmke.Version.Equal(cmke.Version) {
+		d.Add(launchpadChangeMKEUpgrade, fmt.Sprintf("%s → %s", lmke.Version.ValueString(), cmke.Version.ValueString()))
+	}
+	if !lmke.AdminUsername.Equal(cmke.AdminUsername) || !lmke.AdminPassword.Equal(cmke.AdminPassword) {
+		d.Add(launchpadChangeCredential, "mke admin")
+	}
+	lmke.Version, cmke.Version = types.String{}, types.String{}
+	lmke.AdminUsername, cmke.AdminUsername = types.String{}, types.String{}
+	lmke.AdminPassword, cmke.AdminPassword = types.String{}, types.String{}
+	if !reflect.DeepEqual(lmke, cmke) {
+		d.Add(launchpadChangeOther, "mke")
+	}
+
+	if len(ls.Spec.MSR) != len(c.Spec.MSR) {
+		d.Add(launchpadChangeOther, "msr")
+	} else {
+		for i := range c.Spec.MSR {
+			lmsr, cmsr := ls.Spec.MSR[i], c.Spec.MSR[i]
+			if lmsr.Version.ValueString() == "" && !cmsr.Version.Equal(lmsr.Version) {
+				d.Add(launchpadChangeInstall, "MSR")
+			} else if !lmsr.Version.Equal(cmsr.Version) {
+				d.Add(launchpadChangeMSRUpgrade, fmt.Sprintf("%s → %s", lmsr.Version.ValueString(), cmsr.Version.ValueString()))
+			}
+			lmsr.Version, cmsr.Version = types.String{}, types.String{}
+			if !reflect.DeepEqual(lmsr, cmsr) {
+				d.Add(launchpadChangeOther, "msr")
+			}
+		}
+	}
+
+	lhosts := map[string]launchpadModelSpecHost{}
+	for _, h := range ls.Spec.Hosts {
+		lhosts[h.Address()] = h
+	}
+	chosts := map[string]bool{}
+	for _, ch := range c.Spec.Hosts {
+		address := ch.Address()
+		chosts[address] = true
+
+		lh, ok := lhosts[address]
+		if !ok {
+			d.Add(launchpadChangeHostAdded, address)
+			continue
+		}
+
+		if !lh.Role.Equal(ch.Role) {
+			d.Add(launchpadChangeOther, address)
+		}
+		if !reflect.DeepEqual(lh.Hooks, ch.Hooks) {
+			d.Add(launchpadChangeHook, address)
+		}
+		if !reflect.DeepEqual(lh.SSH, ch.SSH) || !reflect.DeepEqual(lh.WinRM, ch.WinRM) {
+			d.Add(launchpadChangeCredential, address)
+		}
+		ldc, lerr := lh.MCRDaemonConfig(ls.Spec.MCR)
+		cdc, cerr := ch.MCRDaemonConfig(c.Spec.MCR)
+		unknown := ch.DaemonConfig.IsUnknown() || c.Spec.MCR.DaemonConfig.IsUnknown()
+		if lerr != nil || cerr != nil || unknown || launchpadDaemonConfigChanged(ldc, cdc) {
+			d.Add(launchpadChangeMCRConfig, address)
+		}
+
+		lh.Role, ch.Role = types.String{}, types.String{}
+		lh.Hooks, ch.Hooks = nil, nil
+		lh.SSH, ch.SSH = nil, nil
+		lh.WinRM, ch.WinRM = nil, nil
+		lh.DaemonConfig, ch.DaemonConfig = types.String{}, types.String{}
+		if !reflect.DeepEqual(lh, ch) {
+			d.Add(launchpadChangeOther, address)
+		}
+	}
+	for _, lh := range ls.Spec.Hosts {
+		if !chosts[lh.Address()] {
+			d.Add(launchpadChangeHostRemoved, lh.Address())
+		}
+	}
+
+	return d
+}
+
+// HasMKETLS are MKE TLS certificates configured, and known so that they can be validated.
+func (ls launchpadModel) HasMKETLS() bool {
+	set := false
+	for _, v := range []types.String{ls.Spec.MKE.CACertPath, ls.Spec.MKE.CertPath, ls.Spec.MKE.KeyPath, ls.Spec.MKE.CACertData, ls.Spec.MKE.CertData, ls.Spec.MKE.KeyData} {
+		if v.IsUnknown() {
+			return false
+		}
+		set = set || v.ValueString() != ""
+	}
+	return set
+}
+
+// Refresh update this state object with what was discovered on the cluster hosts.
+//
+// Hosts which are no longer part of the swarm are dropped, and product versions are
+// replaced with the installed versions if they differ, so that terraform sees the drift.
+func (ls *launchpadModel) Refresh(d *launchpadDiscovery) {
+	mccHosts := d.Config.Spec.Hosts
+
+	hosts := []launchpadModelSpecHost{}
+	for i, host := range ls.Spec.Hosts {
+		if i >= len(mccHosts) || !d.IsSwarmMember(mccHosts[i]) {
+			continue
+		}
+		hosts = append(hosts, host)
+
+		if mcrVersion := mccHosts[i].Metadata.MCRVersion; !versionMatches(ls.Spec.MCR.Version.ValueString(), mcrVersion) {
+			ls.Spec.MCR.Version = types.StringValue(mcrVersion)
+		}
+	}
+	ls.Spec.Hosts = hosts
+
+	mkeVersion := ""
+	if mkeMeta := d.Config.Spec.MKE.Metadata; mkeMeta != nil && mkeMeta.Installed {
+		mkeVersion = mkeMeta.InstalledVersion
+	}
+	if !versionMatches(ls.Spec.MKE.Version.ValueString(), mkeVersion) {
+		ls.Spec.MKE.Version = types.StringValue(mkeVersion)
+	}
+
+	for i, msr := range ls.Spec.MSR {
+		msrVersion := ""
+		for _, h := range d.Config.Spec.MSRs() {
+			if h.MSRMetadata != nil && h.MSRMetadata.Installed {
+				msrVersion = h.MSRMetadata.InstalledVersion
+				break
+			}
+		}
+		if !versionMatches(msr.Version.ValueString(), msrVersion) {
+			ls.Spec.MSR[i].Version = types.StringValue(msrVersion)
+		}
+	}
+}
+
+// versionMatches does an installed version satisfy a configured version, which may be only a version prefix like "23.0".
+func versionMatches(configured, installed string) bool {
+	return installed == configured || strings.HasPrefix(installed, configured+".")
+}
+
+type launchpadModelMetadata struct {
+	Name types.String `tfsdk:"name" json:"name"`
+}
+
+type launchpadModelSpec struct {
+	Cluster []launchpadModelCluster    `tfsdk:"cluster"`
+	Airgap  []launchpadModelSpecAirgap `tfsdk:"airgap"`
+	Hosts   []launchpadModelSpecHost   `tfsdk:"host"`
+	MCR     launchpadModelSpecMCR      `tfsdk:"mcr"`
+	MKE     launchpadModelSpecMKE      `tfsdk:"mke"`
+	MSR     []launchpadModelSpecMSR    `tfsdk:"msr"`
+}
+
+type launchpadModelCluster struct {
+	Prune             types.Bool `tfsdk:"prune"`
+	ResetRemovedHosts types.Bool `tfsdk:"reset_removed_hosts"`
+}
+type launchpadModelSpecAirgap struct {
+	ImageBundle types.String `tfsdk:"image_bundle"`
+}
+
+type launchpadModelSpecMCR struct {
+	Version           types.String `tfsdk:"version"`
+	Channel           types.String `tfsdk:"channel"`
+	InstallURLLinux   types.String `tfsdk:"install_url_linux"`
+	InstallURLWindows types.String `tfsdk:"install_url_windows"`
+	RepoURL           types.String `tfsdk:"repo_url"`
+	DaemonConfig      types.String `tfsdk:"daemon_config"`
+}
+
+type launchpadModelSpecMKE struct {
+	AdminPassword       types.String `tfsdk:"admin_password"`
+	AdminUsername       types.String `tfsdk:"admin_username"`
+	ImageRepo           types.String `tfsdk:"image_repo"`
+	Version             types.String `tfsdk:"version"`
+	InstallFlags        types.List   `tfsdk:"install_flags"`
+	UpgradeFlags        types.List   `tfsdk:"upgrade_flags"`
+	SwarmInstallFlags   types.List   `tfsdk:"swarm_install_flags"`
+	SwarmUpdateCommands types.List   `tfsdk:"swarm_update_commands"`
+	LicenseFilePath     types.String `tfsdk:"license_file_path"`
+	ConfigData          types.String `tfsdk:"config_data"`
+	CACertPath          types.String `tfsdk:"ca_cert_path"`
+	CertPath            types.String `tfsdk:"cert_path"`
+	KeyPath             types.String `tfsdk:"key_path"`
+	CACertData          types.String `tfsdk:"ca_cert_data"`
+	CertData            types.String `tfsdk:"cert_data"`
+	KeyData             types.String `tfsdk:"key_data"`
+
+	CloudProvider []launchpadModelSpecMKECloudProvider `tfsdk:"cloud_provider"`
+}
+
+type launchpadModelSpecMKECloudProvider struct {
+	Provider   types.String `tfsdk:"provider"`
+	ConfigFile types.String `tfsdk:"config_file"`
+	ConfigData types.String `tfsdk:"config_data"`
+}
+
+type launchpadModelSpecMSR struct {
+	ImageRepo    types.String `tfsdk:"image_repo"`
+	Version      types.String `tfsdk:"version"`
+	ReplicaIDs   types.String `tfsdk:"replica_ids"`
+	CACertData   types.String `tfsdk:"ca_cert_data"`
+	CertData     types.String `tfsdk:"cert_data"`
+	KeyData      types.String `tfsdk:"key_data"`
+	InstallFlags types.List   `tfsdk:"install_flags"`
+	UpgradeFlags types.List   `tfsdk:"upgrade_flags"`
+}
+
+type launchpadModelSpecHost struct {
+	Role             types.String `tfsdk:"role"`
+	Environment      types.Map    `tfsdk:"environment"`
+	ImageDir         types.String `tfsdk:"image_dir"`
+	PrivateInterface types.String `tfsdk:"private_interface"`
+	DaemonConfig     types.String `tfsdk:"daemon_config"`
+
+	Hooks []launchpadModelSpecHostHooks `tfsdk:"hooks"`
+	SSH   []launchpadModelSpecHostSSH   `tfsdk:"ssh"`
+	WinRM []launchpadModelSpecHostWinrm `tfsdk:"winrm"`
+}
+
+// Prune should launchpad remove swarm nodes which are no longer in the spec.
+func (ls launchpadModel) Prune() bool {
+	for _, c := range ls.Spec.Cluster {
+		if c.Prune.ValueBool() {
+			return true
+		}
+	}
+	return false
+}
+
+// ResetRemovedHosts should hosts which are pruned from the cluster also be reset.
+func (ls launchpadModel) ResetRemovedHosts() bool {
+	for _, c := range ls.Spec.Cluster {
+		if c.ResetRemovedHosts.ValueBool() {
+			return true
+		}
+	}
+	return false
+}
+
+// Airgap is the cluster installed without internet access.
+func (ls launchpadModel) Airgap() bool {
+	return len(ls.Spec.Airgap) > 0
+}
+
+// AirgapImageBundle the local image bundle which is loaded on the hosts, or an empty string.
+func (ls launchpadModel) AirgapImageBundle() string {
+	for _, a := range ls.Spec.Airgap {
+		return a.ImageBundle.ValueString()
+	}
+	return ""
+}
+
+// HostCount how many hosts have the role, or how many hosts there are for an empty role.
+func (ls launchpadModel) HostCount(role string) int {
+	count := 0
+	for _, h := range ls.Spec.Hosts {
+		if role == "" || h.Role.ValueString() == role {
+			count++
+		}
+	}
+	return count
+}
+
+// Host the host with a connection address, or an empty host if there is none.
+func (ls launchpadModel) Host(address string) launchpadModelSpecHost {
+	for _, h := range ls.Spec.Hosts {
+		if h.Address() == address {
+			return h
+		}
+	}
+	return launchpadModelSpecHost{}
+}
+
+// Address the host connection address, which identifies the host.
+func (h launchpadModelSpecHost) Address() string {
+	for _, hssh := range h.SSH {
+		return hssh.Address.ValueString()
+	}
+	for _, hwinrm := range h.WinRM {
+		return hwinrm.Address.ValueString()
+	}
+	return ""
+}
+
+// launchpadHostPath the attribute path of the i-th spec host.
+func launchpadHostPath(i int) path.Path {
+	return path.Root("spec").AtName("host").AtListIndex(i)
+}
+
+type launchpadModelSpecHostHooks struct {
+	Apply []launchpadModelSpecHostHookAction `tfsdk:"apply"`
+	Reset []launchpadModelSpecHostHookAction `tfsdk:"reset"`
+}
+type launchpadModelSpecHostHookAction struct {
+	Before types.List `tfsdk:"before"`
+	After  types.List `tfsdk:"after"`
+}
+type launchpadModelSpecHostSSH struct {
+	Address    types.String `tfsdk:"address"`
+	KeyPath    types.String `tfsdk:"key_path"`
+	PrivateKey types.String `tfsdk:"private_key"`
+	UseAgent   types.Bool   `tfsdk:"use_agent"`
+	HostKey    types.String `tfsdk:"host_key"`
+	User       types.String `tfsdk:"user"`
+	Port       types.Int64  `tfsdk:"port"`
+
+	Bastion []launchpadSSHBastionModel `tfsdk:"bastion"`
+}
+type launchpadModelSpecHostWinrm struct {
+	Address  types.String `tfsdk:"address"`
+	User     types.String `tfsdk:"user"`
+	Password types.String `tfsdk:"password"`
+	Port     types.Int64  `tfsdk:"port"`
+	UseHTTPS types.Bool   `tfsdk:"use_https"`
+	Insecure types.Bool   `tfsdk:"insecure"`
+
+	CACertPath    types.String `tfsdk:"ca_cert_path"`
+	CACertData    types.String `tfsdk:"ca_cert_data"`
+	CertPath      types.String `tfsdk:"cert_path"`
+	CertData      types.String `tfsdk:"cert_data"`
+	KeyPath       types.String `tfsdk:"key_path"`
+	KeyData       types.String `tfsdk:"key_data"`
+	TLSServerName types.String `tfsdk:"tls_server_name"`
+}
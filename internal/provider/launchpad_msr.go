@@ -0,0 +1,12 @@
+package provider
+
+// launchpadMSRInstallFlagsChanged do the MSR install flags change for an MSR which is already installed.
+//
+// Launchpad only passes the install flags to the MSR bootstrapper when it installs MSR, so
+// changing them does not reconfigure existing replicas.
+func launchpadMSRInstallFlagsChanged(sls, pls launchpadModel) bool {
+	if len(sls.Spec.MSR) == 0 || sls.HostCount(HostRoleMSR) == 0 || len(pls.Spec.MSR) == 0 || pls.HostCount(HostRoleMSR) == 0 {
+		return false
+	}
+	return !sls.Spec.MSR[0].InstallFlags.Equal(pls.Spec.MSR[0].InstallFlags)
+}
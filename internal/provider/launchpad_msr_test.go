@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestLaunchpadMSRInstallFlagsChanged(t *testing.T) {
+	sls := testLaunchpadDiffModel()
+	sls.Spec.MSR[0].InstallFlags = testStringList("--ucp-insecure-tls")
+
+	pls := testLaunchpadDiffModel()
+	pls.Spec.MSR[0].InstallFlags = testStringList("--ucp-insecure-tls")
+	pls.Spec.MSR[0].UpgradeFlags = testStringList("--debug")
+	if launchpadMSRInstallFlagsChanged(sls, pls) {
+		t.Error("did not expect an install flags change for upgrade flags")
+	}
+
+	pls.Spec.MSR[0].InstallFlags = testStringList("--ucp-insecure-tls", "--dtr-external-url=msr.example.org")
+	if !launchpadMSRInstallFlagsChanged(sls, pls) {
+		t.Error("expected an install flags change")
+	}
+
+	// without MSR hosts in the state, MSR is installed with the new flags
+	sls.Spec.Hosts = sls.Spec.Hosts[:2]
+	if launchpadMSRInstallFlagsChanged(sls, pls) {
+		t.Error("did not expect an install flags change when MSR is not installed yet")
+	}
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// launchpadPlannedActions describe what launchpad will do to get from the prior state to the planned state.
+//
+// Without a prior state the cluster is installed from scratch.
+func launchpadPlannedActions(sls *launchpadModel, pls launchpadModel) []string {
+	actions := []string{}
+
+	if sls == nil {
+		actions = append(actions,
+			fmt.Sprintf("install MCR %s on %s", pls.Spec.MCR.Version.ValueString(), launchpadHostCount(pls.HostCount(""), "")),
+			fmt.Sprintf("install MKE %s on %s", pls.Spec.MKE.Version.ValueString(), launchpadHostCount(pls.HostCount("manager"), "manager")),
+		)
+		if msrs := pls.HostCount(HostRoleMSR); len(pls.Spec.MSR) > 0 && msrs > 0 {
+			actions = append(actions, fmt.Sprintf("install MSR %s on %s", pls.Spec.MSR[0].Version.ValueString(), launchpadHostCount(msrs, HostRoleMSR)))
+		}
+		return actions
+	}
+
+	d := sls.Diff(pls)
+
+	// products which refresh found not installed
+	for _, product := range d.Details(launchpadChangeInstall) {
+		switch product {
+		case "MCR":
+			actions = append(actions, fmt.Sprintf("install MCR %s on %s", pls.Spec.MCR.Version.ValueString(), launchpadHostCount(pls.HostCount(""), "")))
+		case "MKE":
+			actions = append(actions, fmt.Sprintf("install MKE %s on %s", pls.Spec.MKE.Version.ValueString(), launchpadHostCount(pls.HostCount("manager"), "manager")))
+		case "MSR":
+			actions = append(actions, fmt.Sprintf("install MSR %s on %s", pls.Spec.MSR[0].Version.ValueString(), launchpadHostCount(pls.HostCount(HostRoleMSR), HostRoleMSR)))
+		}
+	}
+
+	if d.Has(launchpadChangeMCRUpgrade) {
+		actions = append(actions, fmt.Sprintf("upgrade MCR %s → %s on %s", sls.Spec.MCR.Version.ValueString(), pls.Spec.MCR.Version.ValueString(), launchpadHostCount(pls.HostCount(""), "")))
+	}
+	if d.Has(launchpadChangeMKEUpgrade) {
+		actions = append(actions, fmt.Sprintf("upgrade MKE %s → %s on %s", sls.Spec.MKE.Version.ValueString(), pls.Spec.MKE.Version.ValueString(), launchpadHostCount(pls.HostCount("manager"), "manager")))
+	}
+
+	smsr, pmsr := len(sls.Spec.MSR) > 0 && sls.HostCount(HostRoleMSR) > 0, len(pls.Spec.MSR) > 0 && pls.HostCount(HostRoleMSR) > 0
+	switch {
+	case !smsr && pmsr:
+		actions = append(actions, fmt.Sprintf("enable MSR: install MSR %s on %s", pls.Spec.MSR[0].Version.ValueString(), launchpadHostCount(pls.HostCount(HostRoleMSR), HostRoleMSR)))
+	case smsr && !pmsr:
+		actions = append(actions, fmt.Sprintf("disable MSR %s: it is no longer managed, launchpad does not uninstall it", sls.Spec.MSR[0].Version.ValueString()))
+	case d.Has(launchpadChangeMSRUpgrade):
+		actions = append(actions, fmt.Sprintf("upgrade MSR %s → %s on %s", sls.Spec.MSR[0].Version.ValueString(), pls.Spec.MSR[0].Version.ValueString(), launchpadHostCount(pls.HostCount(HostRoleMSR), HostRoleMSR)))
+	}
+
+	for _, address := range launchpadSorted(d.Details(launchpadChangeMCRConfig)) {
+		actions = append(actions, fmt.Sprintf("update the MCR daemon.json of host %s, and restart MCR on it if the daemon.json changed", address))
+	}
+	for _, address := range launchpadSorted(d.Details(launchpadChangeHostAdded)) {
+		actions = append(actions, fmt.Sprintf("join host %s as %s", address, pls.Host(address).Role.ValueString()))
+	}
+	for _, address := range launchpadSorted(d.Details(launchpadChangeHostRemoved)) {
+		role := sls.Host(address).Role.ValueString()
+		actions = append(actions, fmt.Sprintf("remove host %s (%s) from the swarm: %s", address, role, strings.Join(launchpadRemovalSteps(role, pls.ResetRemovedHosts()), ", ")))
+	}
+	for _, address := range launchpadSorted(d.Details(launchpadChangeHook)) {
+		actions = append(actions, fmt.Sprintf("update the hooks of host %s, they run on the next launchpad apply or reset", address))
+	}
+	if d.Has(launchpadChangeCredential) {
+		actions = append(actions, "update connection credentials in the state, launchpad does not run for them")
+	}
+	if d.Has(launchpadChangeOther) {
+		actions = append(actions, fmt.Sprintf("apply other changes to: %v", launchpadSorted(d.Details(launchpadChangeOther))))
+	}
+
+	return actions
+}
+
+// launchpadHostCount a phrase for a number of hosts with a role, e.g. "3 managers", where an empty role means any host.
+func launchpadHostCount(count int, role string) string {
+	noun := role
+	switch role {
+	case "":
+		noun = "host"
+	case HostRoleMSR:
+		noun = "MSR host"
+	}
+	if count != 1 {
+		noun += "s"
+	}
+	return fmt.Sprintf("%d %s", count, noun)
+}
+
+// launchpadSorted a sorted copy of a list of strings.
+func launchpadSorted(vs []string) []string {
+	sorted := append([]string{}, vs...)
+	sort.Strings(sorted)
+	return sorted
+}
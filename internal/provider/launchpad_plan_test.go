@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestLaunchpadPlannedActions(t *testing.T) {
+	pls := testLaunchpadDiffModel()
+
+	expected := []string{
+		"install MCR 23.0 on 3 hosts",
+		"install MKE 3.6.4 on 1 manager",
+		"install MSR 2.9.4 on 1 MSR host",
+	}
+	if actions := launchpadPlannedActions(nil, pls); !reflect.DeepEqual(actions, expected) {
+		t.Errorf("unexpected install actions: %v", actions)
+	}
+
+	sls := testLaunchpadDiffModel()
+	pls.Spec.MKE.Version = types.StringValue("3.7.1")
+	pls.Spec.MSR = nil
+	pls.Spec.Hosts = pls.Spec.Hosts[:2]
+
+	expected = []string{
+		"upgrade MKE 3.6.4 → 3.7.1 on 1 manager",
+		"disable MSR 2.9.4: it is no longer managed, launchpad does not uninstall it",
+		"remove host msr1.example.org (msr) from the swarm: remove the MSR replica, drain, leave the swarm, remove the swarm node",
+		"apply other changes to: [msr]",
+	}
+	if actions := launchpadPlannedActions(&sls, pls); !reflect.DeepEqual(actions, expected) {
+		t.Errorf("unexpected update actions: %v", actions)
+	}
+
+	if actions := launchpadPlannedActions(&sls, sls); len(actions) != 0 {
+		t.Errorf("expected no actions without changes, got %v", actions)
+	}
+
+	// refresh found MKE and MSR not installed
+	sls, pls = testLaunchpadDiffModel(), testLaunchpadDiffModel()
+	sls.Spec.MKE.Version = types.StringValue("")
+	sls.Spec.MSR[0].Version = types.StringValue("")
+
+	expected = []string{
+		"install MKE 3.6.4 on 1 manager",
+		"install MSR 2.9.4 on 1 MSR host",
+	}
+	if actions := launchpadPlannedActions(&sls, pls); !reflect.DeepEqual(actions, expected) {
+		t.Errorf("unexpected actions for products which are not installed: %v", actions)
+	}
+}
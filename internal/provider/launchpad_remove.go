@@ -0,0 +1,282 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+
+	mcc_msr "github.com/Mirantis/mcc/pkg/msr"
+	mcc_phase "github.com/Mirantis/mcc/pkg/phase"
+	mcc_common_api "github.com/Mirantis/mcc/pkg/product/common/api"
+	mcc_common_phase "github.com/Mirantis/mcc/pkg/product/common/phase"
+	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
+	mcc_mke_phase "github.com/Mirantis/mcc/pkg/product/mke/phase"
+	mcc_swarm "github.com/Mirantis/mcc/pkg/swarm"
+	k0s_rig_exec "github.com/k0sproject/rig/exec"
+)
+
+// launchpadHostRemoval gracefully removes hosts from the cluster, before launchpad applies the remaining spec.
+//
+// The removal runs as an mcc phase on the prior cluster config, which still has the removed
+// hosts, so that it can use the connections to the hosts that are removed as well as to
+// the hosts that remain.
+type launchpadHostRemoval struct {
+	mcc_phase.BasicPhase
+
+	addresses map[string]bool
+	reset     bool
+
+	steps  map[string][]string
+	errors map[string]error
+}
+
+// removeClusterHosts remove the hosts with the addresses from the cluster, optionally also resetting (uninstalling MCR on) them.
+func removeClusterHosts(cc *mcc_mke_api.ClusterConfig, addresses []string, reset bool) (*launchpadHostRemoval, error) {
+	r := &launchpadHostRemoval{
+		addresses: map[string]bool{},
+		reset:     reset,
+		steps:     map[string][]string{},
+		errors:    map[string]error{},
+	}
+	for _, a := range addresses {
+		r.addresses[a] = true
+	}
+
+	phaseManager := mcc_phase.NewManager(cc)
+	phaseManager.AddPhases(
+		&mcc_common_phase.Connect{},
+		&mcc_mke_phase.DetectOS{},
+		&mcc_mke_phase.GatherFacts{},
+	)
+	if reset {
+		// the MCR uninstall uses the MCR installation script
+		phaseManager.AddPhase(&mcc_mke_phase.DownloadInstaller{})
+	}
+	phaseManager.AddPhases(
+		r,
+		&mcc_common_phase.Disconnect{},
+	)
+
+	if err := phaseManager.Run(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Title for the phase.
+func (r *launchpadHostRemoval) Title() string {
+	return "Remove Hosts From The Cluster"
+}
+
+// Run remove the hosts one at a time, so that removing managers never costs the swarm more than one manager at once.
+//
+// A failing host does not stop the removal of the others, the failures are reported per host.
+func (r *launchpadHostRemoval) Run() error {
+	removed, remaining := launchpadRemovalOrder(r.Config.Spec.Hosts, r.addresses)
+
+	remainingSpec := &mcc_mke_api.ClusterSpec{Hosts: remaining}
+	swarmLeader := remainingSpec.SwarmLeader()
+	if swarmLeader == nil {
+		return fmt.Errorf("there is no remaining manager to remove the hosts from the swarm with")
+	}
+	remainingMSRs := remainingSpec.MSRs()
+	msrLeader := remainingMSRs.Find(mcc_mke_api.IsMSRInstalled)
+
+	for _, h := range removed {
+		if err := r.removeHost(h, swarmLeader, msrLeader); err != nil {
+			r.errors[h.Address()] = err
+		}
+	}
+
+	return nil
+}
+
+// removeHost take a single host out of the cluster.
+func (r *launchpadHostRemoval) removeHost(h, swarmLeader, msrLeader *mcc_mke_api.Host) error {
+	address := h.Address()
+
+	nodeID, err := mcc_swarm.NodeID(h)
+	if err != nil {
+		return fmt.Errorf("could not get the swarm node id: %w", err)
+	}
+	if nodeID == "" {
+		r.steps[address] = append(r.steps[address], "not a swarm member")
+	} else {
+		if mcc_mke_api.IsMSRInstalled(h) {
+			if err := r.removeMSRReplica(h, swarmLeader, msrLeader); err != nil {
+				return err
+			}
+		}
+
+		// MKE carries the swarm availability over to kubernetes, so this also drains the kubernetes node
+		if err := swarmLeader.Exec(swarmLeader.Configurer.DockerCommandf("node update --availability drain %s", nodeID)); err != nil {
+			return fmt.Errorf("could not drain the node: %w", err)
+		}
+		r.steps[address] = append(r.steps[address], "drained")
+
+		if h.Role == "manager" {
+			if err := swarmLeader.Exec(swarmLeader.Configurer.DockerCommandf("node demote %s", nodeID)); err != nil {
+				return fmt.Errorf("could not demote the manager: %w", err)
+			}
+			r.steps[address] = append(r.steps[address], "demoted")
+		}
+
+		// the host may not have seen its demotion yet, and would refuse to leave as a manager
+		if err := h.Exec(h.Configurer.DockerCommandf("swarm leave --force")); err != nil {
+			return fmt.Errorf("could not leave the swarm: %w", err)
+		}
+		r.steps[address] = append(r.steps[address], "left the swarm")
+
+		if err := swarmLeader.Exec(swarmLeader.Configurer.DockerCommandf("node rm --force %s", nodeID)); err != nil {
+			return fmt.Errorf("could not remove the swarm node: %w", err)
+		}
+		r.steps[address] = append(r.steps[address], "swarm node removed")
+	}
+
+	if r.reset {
+		return r.resetHost(address, h, h.Hooks, func() error {
+			return h.Configurer.UninstallMCR(h, h.Metadata.MCRInstallScript, r.Config.Spec.MCR)
+		})
+	}
+
+	return nil
+}
+
+// launchpadHookHost the part of a host which runs hook commands, as in the mcc RunHooks phase.
+type launchpadHookHost interface {
+	ExecAll([]string) error
+}
+
+// resetHost uninstall MCR from a removed host between its reset before and after hooks, as a launchpad reset would.
+func (r *launchpadHostRemoval) resetHost(address string, h launchpadHookHost, hooks mcc_common_api.Hooks, uninstall func() error) error {
+	if before := hooks["reset"]["before"]; len(before) > 0 {
+		if err := h.ExecAll(before); err != nil {
+			return fmt.Errorf("the reset before hooks failed: %w", err)
+		}
+		r.steps[address] = append(r.steps[address], "reset before hooks run")
+	}
+
+	if err := uninstall(); err != nil {
+		return fmt.Errorf("could not uninstall MCR: %w", err)
+	}
+	r.steps[address] = append(r.steps[address], "MCR uninstalled")
+
+	if after := hooks["reset"]["after"]; len(after) > 0 {
+		if err := h.ExecAll(after); err != nil {
+			return fmt.Errorf("the reset after hooks failed: %w", err)
+		}
+		r.steps[address] = append(r.steps[address], "reset after hooks run")
+	}
+
+	return nil
+}
+
+// removeMSRReplica remove the MSR replica on a host, using a remaining MSR replica, or destroy MSR if no replica remains.
+func (r *launchpadHostRemoval) removeMSRReplica(h, swarmLeader, msrLeader *mcc_mke_api.Host) error {
+	address := h.Address()
+
+	if msrLeader == nil {
+		if err := mcc_msr.Cleanup([]*mcc_mke_api.Host{h}, swarmLeader); err != nil {
+			return fmt.Errorf("could not destroy the last MSR replica: %w", err)
+		}
+		r.steps[address] = append(r.steps[address], "last MSR replica destroyed")
+		return nil
+	}
+
+	removeFlags := mcc_common_api.Flags{
+		fmt.Sprintf("--replica-ids %s", h.MSRMetadata.ReplicaID),
+		fmt.Sprintf("--existing-replica-id %s", msrLeader.MSRMetadata.ReplicaID),
+	}
+	removeFlags.MergeOverwrite(mcc_msr.BuildMKEFlags(r.Config))
+	if r.Config.Spec.MSR != nil {
+		for _, f := range mcc_msr.PluckSharedInstallFlags(r.Config.Spec.MSR.InstallFlags, mcc_msr.SharedInstallRemoveFlags) {
+			removeFlags.AddOrReplace(f)
+		}
+	}
+
+	removeCmd := msrLeader.Configurer.DockerCommandf("run -i --rm %s remove %s", msrLeader.MSRMetadata.InstalledBootstrapImage, removeFlags.Join())
+	if err := msrLeader.Exec(removeCmd, k0s_rig_exec.StreamOutput()); err != nil {
+		return fmt.Errorf("could not remove MSR replica %s: %w", h.MSRMetadata.ReplicaID, err)
+	}
+	r.steps[address] = append(r.steps[address], fmt.Sprintf("MSR replica %s removed", h.MSRMetadata.ReplicaID))
+	return nil
+}
+
+// Steps what was done to remove the host.
+func (r *launchpadHostRemoval) Steps(address string) []string {
+	return r.steps[address]
+}
+
+// Error why removing the host failed, if it did.
+func (r *launchpadHostRemoval) Error(address string) error {
+	return r.errors[address]
+}
+
+// launchpadRemovalOrder split the hosts into those that are removed, in the order to remove them in, and those that remain.
+//
+// MSR hosts go first, so that their replicas are removed while MSR is still healthy, then
+// workers, then managers, so that the swarm keeps as many managers as possible for as long
+// as possible.
+func launchpadRemovalOrder(hosts mcc_mke_api.Hosts, addresses map[string]bool) (mcc_mke_api.Hosts, mcc_mke_api.Hosts) {
+	removed, remaining := mcc_mke_api.Hosts{}, mcc_mke_api.Hosts{}
+	for _, h := range hosts {
+		if addresses[h.Address()] {
+			removed = append(removed, h)
+		} else {
+			remaining = append(remaining, h)
+		}
+	}
+
+	order := map[string]int{HostRoleMSR: 0, "worker": 1, "manager": 2}
+	sort.SliceStable(removed, func(i, j int) bool {
+		return order[removed[i].Role] < order[removed[j].Role]
+	})
+
+	return removed, remaining
+}
+
+// launchpadRemovalSteps describe what removing a host with a role does.
+func launchpadRemovalSteps(role string, reset bool) []string {
+	steps := []string{}
+	if role == HostRoleMSR {
+		steps = append(steps, "remove the MSR replica")
+	}
+	steps = append(steps, "drain")
+	if role == "manager" {
+		steps = append(steps, "demote")
+	}
+	steps = append(steps, "leave the swarm", "remove the swarm node")
+	if reset {
+		steps = append(steps, "run the reset hooks and uninstall MCR")
+	}
+	return steps
+}
+
+// launchpadRemovedHosts the addresses of the hosts which are removed from the swarm, e.g. "worker1.example.org (worker)".
+func launchpadRemovedHosts(sls, pls launchpadModel) []string {
+	removed := []string{}
+	for _, address := range launchpadSorted(sls.Diff(pls).Details(launchpadChangeHostRemoved)) {
+		removed = append(removed, fmt.Sprintf("%s (%s)", address, sls.Host(address).Role.ValueString()))
+	}
+	return removed
+}
+
+// launchpadHostRemovalCheck refuse a host removal which would leave the swarm without a manager.
+//
+// The removed managers are demoted one at a time, so the swarm keeps its raft quorum while it
+// shrinks. The hosts are removed before new hosts join though, so one of the current managers
+// has to remain. With spec.cluster.prune, launchpad also removes managers which were never in
+// the state, but those are unknown here.
+func launchpadHostRemovalCheck(sls, pls launchpadModel) error {
+	removed := 0
+	for _, address := range sls.Diff(pls).Details(launchpadChangeHostRemoved) {
+		if sls.Host(address).Role.ValueString() == "manager" {
+			removed++
+		}
+	}
+
+	if removed > 0 && sls.HostCount("manager")-removed < 1 {
+		return fmt.Errorf("removing %s would leave the swarm without a manager, as hosts are removed before new managers join", launchpadHostCount(removed, "manager"))
+	}
+	return nil
+}
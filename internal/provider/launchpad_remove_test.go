@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	mcc_common_api "github.com/Mirantis/mcc/pkg/product/common/api"
+	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	k0s_rig "github.com/k0sproject/rig"
+)
+
+func TestLaunchpadRemovalOrder(t *testing.T) {
+	host := func(role, address string) *mcc_mke_api.Host {
+		return &mcc_mke_api.Host{
+			Role:       role,
+			Connection: k0s_rig.Connection{SSH: &k0s_rig.SSH{Address: address, Port: 22}},
+		}
+	}
+	hosts := mcc_mke_api.Hosts{
+		host("manager", "manager1.example.org"),
+		host("manager", "manager2.example.org"),
+		host("manager", "manager3.example.org"),
+		host("worker", "worker1.example.org"),
+		host("msr", "msr1.example.org"),
+		host("msr", "msr2.example.org"),
+	}
+
+	removed, remaining := launchpadRemovalOrder(hosts, map[string]bool{
+		"manager3.example.org": true,
+		"worker1.example.org":  true,
+		"msr2.example.org":     true,
+	})
+
+	order := []string{}
+	for _, h := range removed {
+		order = append(order, h.Address())
+	}
+	if expected := []string{"msr2.example.org", "worker1.example.org", "manager3.example.org"}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected the removal order %v, got %v", expected, order)
+	}
+	if len(remaining) != 3 {
+		t.Errorf("expected 3 remaining hosts, got %d", len(remaining))
+	}
+}
+
+func TestLaunchpadRemovalSteps(t *testing.T) {
+	tests := []struct {
+		role     string
+		reset    bool
+		expected []string
+	}{
+		{role: "worker", expected: []string{"drain", "leave the swarm", "remove the swarm node"}},
+		{role: "manager", expected: []string{"drain", "demote", "leave the swarm", "remove the swarm node"}},
+		{role: "msr", reset: true, expected: []string{"remove the MSR replica", "drain", "leave the swarm", "remove the swarm node", "run the reset hooks and uninstall MCR"}},
+	}
+
+	for _, test := range tests {
+		if steps := launchpadRemovalSteps(test.role, test.reset); !reflect.DeepEqual(steps, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.role, test.expected, steps)
+		}
+	}
+}
+
+// testHookHost records the hook commands which are run on it.
+type testHookHost struct {
+	run *[]string
+}
+
+func (h testHookHost) ExecAll(cmds []string) error {
+	*h.run = append(*h.run, cmds...)
+	return nil
+}
+
+func TestLaunchpadHostRemovalResetHost(t *testing.T) {
+	run := []string{}
+	hooks := mcc_common_api.Hooks{
+		"apply": {"before": {"apply before"}},
+		"reset": {"before": {"reset before"}, "after": {"reset after 1", "reset after 2"}},
+	}
+
+	r := &launchpadHostRemoval{steps: map[string][]string{}}
+	err := r.resetHost("worker1.example.org", testHookHost{run: &run}, hooks, func() error {
+		run = append(run, "uninstall MCR")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected reset error: %s", err)
+	}
+
+	if expected := []string{"reset before", "uninstall MCR", "reset after 1", "reset after 2"}; !reflect.DeepEqual(run, expected) {
+		t.Errorf("expected the reset hooks around the uninstall %v, got %v", expected, run)
+	}
+	if expected := []string{"reset before hooks run", "MCR uninstalled", "reset after hooks run"}; !reflect.DeepEqual(r.Steps("worker1.example.org"), expected) {
+		t.Errorf("unexpected reset steps: %v", r.Steps("worker1.example.org"))
+	}
+
+	run = []string{}
+	r = &launchpadHostRemoval{steps: map[string][]string{}}
+	err = r.resetHost("worker1.example.org", testHookHost{run: &run}, nil, func() error {
+		return fmt.Errorf("uninstall failed")
+	})
+	if err == nil || len(run) != 0 {
+		t.Errorf("expected the failed uninstall without hooks to stop the reset, got %v and %v", err, run)
+	}
+}
+
+func TestLaunchpadHostRemovalCheck(t *testing.T) {
+	// withManagers the test cluster with n managers, numbered from first
+	withManagers := func(first, n int) launchpadModel {
+		ls := testLaunchpadDiffModel()
+
+		manager := ls.Spec.Hosts[0]
+		ls.Spec.Hosts = ls.Spec.Hosts[1:]
+		for i := first; i < first+n; i++ {
+			h := manager
+			h.SSH = []launchpadModelSpecHostSSH{manager.SSH[0]}
+			h.SSH[0].Address = types.StringValue(fmt.Sprintf("manager%d.example.org", i))
+			ls.Spec.Hosts = append(ls.Spec.Hosts, h)
+		}
+		return ls
+	}
+
+	tests := []struct {
+		name   string
+		state  int
+		plan   int
+		shift  int // the planned managers are numbered from 1+shift, so shifted managers are replaced
+		refuse bool
+	}{
+		{name: "one of three", state: 3, plan: 2},
+		{name: "two of three", state: 3, plan: 1},
+		{name: "four of five", state: 5, plan: 1},
+		{name: "one of two", state: 2, plan: 1},
+		{name: "last manager", state: 1, plan: 0, refuse: true},
+		{name: "grow the cluster", state: 3, plan: 5},
+		{name: "replace all managers", state: 3, plan: 3, shift: 3, refuse: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sls := withManagers(1, test.state)
+			pls := withManagers(1+test.shift, test.plan)
+
+			err := launchpadHostRemovalCheck(sls, pls)
+			if test.refuse && err == nil {
+				t.Error("expected the removal to be refused")
+			}
+			if !test.refuse && err != nil {
+				t.Errorf("unexpected removal refusal: %s", err)
+			}
+		})
+	}
+
+	sls, pls := withManagers(1, 3), withManagers(1, 2)
+	if removed := launchpadRemovedHosts(sls, pls); !reflect.DeepEqual(removed, []string{"manager3.example.org (manager)"}) {
+		t.Errorf("unexpected removed hosts: %v", removed)
+	}
+}
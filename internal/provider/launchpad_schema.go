@@ -0,0 +1,665 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// LaunchpadSchemaVersion the resource schema version, which is bumped whenever existing states need an upgrade.
+	LaunchpadSchemaVersion = 1
+	// LaunchpadClusterConfigAPIVersion the launchpad config api version which is passed to launchpad.
+	LaunchpadClusterConfigAPIVersion = "launchpad.mirantis.com/mke/v1.4"
+)
+
+var (
+	// MKECloudProviders the cloud providers that MKE can integrate with.
+	MKECloudProviders = []string{"aws", "azure", "gce", "openstack", "vsphere"}
+	// MKECloudConfigProviders the cloud providers that launchpad can write a cloud config for.
+	MKECloudConfigProviders = []string{"azure", "openstack"}
+)
+
+// launchpadSchema the launchpad_config resource schema.
+func launchpadSchema() schema.Schema {
+	return schema.Schema{
+		Version: LaunchpadSchemaVersion,
+
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Mirantis installation using launchpad, parametrized",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Example identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"skip_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Do not bother uninstalling on destroy",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+
+			"apply_concurrency": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("How many hosts launchpad works on in parallel, defaults to the provider apply_concurrency (%d-%d)", MinApplyConcurrency, MaxApplyConcurrency),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(MinApplyConcurrency, MaxApplyConcurrency),
+				},
+			},
+			"force": schema.BoolAttribute{
+				MarkdownDescription: "Force launchpad apply, even when fact validation fails, e.g. for pre-release product versions",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"disable_cleanup": schema.BoolAttribute{
+				MarkdownDescription: "Do not let launchpad clean up after failed phases, which helps debugging",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"log_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a local file which the full launchpad log of every run is appended to",
+				Optional:            true,
+			},
+			"planned_actions": schema.ListAttribute{
+				MarkdownDescription: "What launchpad does for the planned changes, e.g. \"upgrade MKE 3.6.4 → 3.7.1 on 3 managers\", as computed at plan time, or at apply time when the plan has unknown values",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+
+			"metadata": schema.SingleNestedBlock{
+				MarkdownDescription: "Metadata for the launchpad cluster",
+
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						MarkdownDescription: "Cluster name",
+						Required:            true,
+					},
+				},
+			},
+
+			"spec": schema.SingleNestedBlock{
+				MarkdownDescription: "Launchpad install specifications",
+
+				Blocks: map[string]schema.Block{
+
+					"cluster": schema.ListNestedBlock{
+						MarkdownDescription: "MSR installation configuration",
+
+						Validators: []validator.List{
+							listvalidator.SizeAtMost(1),
+						},
+						NestedObject: schema.NestedBlockObject{
+							Attributes: map[string]schema.Attribute{
+								"prune": schema.BoolAttribute{
+									MarkdownDescription: "Let launchpad also remove the swarm nodes which are not in the spec, including nodes which were joined outside of terraform. Hosts which are removed from the spec are always removed gracefully, prune is only needed for nodes which terraform does not know about. The plan does not list those nodes, and the check that a manager remains does not count them",
+									Optional:            true,
+									Computed:            true,
+									Default:             booldefault.StaticBool(false),
+								},
+								"reset_removed_hosts": schema.BoolAttribute{
+									MarkdownDescription: "Also reset hosts which are removed from the spec: run their reset hooks and uninstall MCR",
+									Optional:            true,
+									Computed:            true,
+									Default:             booldefault.StaticBool(false),
+								},
+							},
+						},
+					},
+
+					"airgap": schema.ListNestedBlock{
+						MarkdownDescription: "Airgapped installation, for hosts without internet access. The plan is refused while the MCR repository and install scripts, or the MKE and MSR image repos, still point at their public defaults. Launchpad then skips the MKE upgrade check and sends no analytics",
+
+						Validators: []validator.List{
+							listvalidator.SizeAtMost(1),
+						},
+						NestedObject: schema.NestedBlockObject{
+							Attributes: map[string]schema.Attribute{
+								"image_bundle": schema.StringAttribute{
+									MarkdownDescription: "Local image bundle (docker save .tar, .tar.gz or .tgz), on the machine running terraform, which launchpad uploads and loads on every host without an image_dir before installing or upgrading MKE and MSR",
+									Optional:            true,
+								},
+							},
+						},
+					},
+
+					"mcr": schema.SingleNestedBlock{
+						MarkdownDescription: "MCR installation configuration",
+
+						Attributes: map[string]schema.Attribute{
+							"version": schema.StringAttribute{
+								MarkdownDescription: "MCR version to install",
+								Required:            true,
+							},
+							"channel": schema.StringAttribute{
+								MarkdownDescription: "Repitory installation channel",
+								Optional:            true,
+								Computed:            true,
+								Default:             stringdefault.StaticString("stable"),
+							},
+							"repo_url": schema.StringAttribute{
+								MarkdownDescription: "Repository installation URL for installation script",
+								Optional:            true,
+								Computed:            true,
+								Default:             stringdefault.StaticString("https://repos.mirantis.com"),
+							},
+							"install_url_linux": schema.StringAttribute{
+								MarkdownDescription: "MCR installation script for linux installations",
+								Optional:            true,
+								Computed:            true,
+								Default:             stringdefault.StaticString("https://get.mirantis.com/"),
+							},
+							"install_url_windows": schema.StringAttribute{
+								MarkdownDescription: "MCR installation script for windows installations",
+								Optional:            true,
+								Computed:            true,
+								Default:             stringdefault.StaticString("https://get.mirantis.com/install.ps1"),
+							},
+							"daemon_config": schema.StringAttribute{
+								MarkdownDescription: "Default MCR daemon.json (JSON object, such as from jsonencode) for all hosts, which the host daemon_config keys override. Launchpad writes the whole daemon.json, and restarts MCR on the hosts whose daemon.json changed",
+								Optional:            true,
+							},
+						},
+					},
+
+					"mke": schema.SingleNestedBlock{
+						MarkdownDescription: "MKE installation configuration",
+
+						Attributes: map[string]schema.Attribute{
+							"version": schema.StringAttribute{
+								MarkdownDescription: "MKE version to install",
+								Required:            true,
+							},
+							"image_repo": schema.StringAttribute{
+								MarkdownDescription: "Image repo for MKE images",
+								Optional:            true,
+								Computed:            true,
+								Default:             stringdefault.StaticString("docker.io/mirantis"),
+							},
+							"admin_username": schema.StringAttribute{
+								MarkdownDescription: "MKE admin user name",
+								Optional:            true,
+								Computed:            true,
+								Default:             stringdefault.StaticString("admin"),
+							},
+							"admin_password": schema.StringAttribute{
+								MarkdownDescription: "MKE admin user password",
+								Required:            true,
+								Sensitive:           true,
+							},
+							"license_file_path": schema.StringAttribute{
+								MarkdownDescription: "MKE license file path",
+								Optional:            true,
+								Computed:            true,
+								Default:             stringdefault.StaticString(""),
+							},
+							"config_data": schema.StringAttribute{
+								MarkdownDescription: "MKE configuration file (toml) contents, which are applied to MKE on install",
+								Optional:            true,
+								Sensitive:           true,
+							},
+							"ca_cert_path": schema.StringAttribute{
+								MarkdownDescription: "Path to the MKE CA certificate (PEM) file",
+								Optional:            true,
+								Validators: []validator.String{
+									stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("ca_cert_data")),
+								},
+							},
+							"cert_path": schema.StringAttribute{
+								MarkdownDescription: "Path to the MKE TLS certificate (PEM) file, which needs a key",
+								Optional:            true,
+								Validators: []validator.String{
+									stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("cert_data")),
+								},
+							},
+							"key_path": schema.StringAttribute{
+								MarkdownDescription: "Path to the MKE TLS private key (PEM) file, which needs a certificate",
+								Optional:            true,
+								Validators: []validator.String{
+									stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("key_data")),
+								},
+							},
+							"ca_cert_data": schema.StringAttribute{
+								MarkdownDescription: "MKE CA certificate (PEM)",
+								Optional:            true,
+							},
+							"cert_data": schema.StringAttribute{
+								MarkdownDescription: "MKE TLS certificate (PEM), which needs a key. Its SANs have to include the manager addresses, or one of the --san install flags",
+								Optional:            true,
+							},
+							"key_data": schema.StringAttribute{
+								MarkdownDescription: "MKE TLS private key (PEM), which needs a certificate",
+								Optional:            true,
+								Sensitive:           true,
+							},
+
+							"install_flags": schema.ListAttribute{
+								MarkdownDescription: "Optional MKE bootstrapper install flags",
+								ElementType:         types.StringType,
+								Optional:            true,
+								Computed:            true,
+								Default:             listdefault.StaticValue(types.ListNull(types.StringType)),
+							},
+							"upgrade_flags": schema.ListAttribute{
+								MarkdownDescription: "Optional MKE bootstrapper update flags",
+								ElementType:         types.StringType,
+								Optional:            true,
+								Computed:            true,
+								Default:             listdefault.StaticValue(types.ListNull(types.StringType)),
+							},
+							"swarm_install_flags": schema.ListAttribute{
+								MarkdownDescription: "Optional docker swarm init flags, such as --data-path-port, which are only used when the swarm is initialized. --advertise-addr is set by launchpad",
+								ElementType:         types.StringType,
+								Optional:            true,
+								Computed:            true,
+								Default:             listdefault.StaticValue(types.ListNull(types.StringType)),
+							},
+							"swarm_update_commands": schema.ListAttribute{
+								MarkdownDescription: "Optional docker commands, such as \"swarm update --cert-expiry 2160h\", which are run on the swarm leader right after the swarm is initialized",
+								ElementType:         types.StringType,
+								Optional:            true,
+								Computed:            true,
+								Default:             listdefault.StaticValue(types.ListNull(types.StringType)),
+							},
+						},
+
+						Blocks: map[string]schema.Block{
+							"cloud_provider": schema.ListNestedBlock{
+								MarkdownDescription: "MKE cloud provider integration, which is set up when MKE is installed",
+
+								Validators: []validator.List{
+									listvalidator.SizeAtMost(1),
+								},
+								NestedObject: schema.NestedBlockObject{
+									Attributes: map[string]schema.Attribute{
+										"provider": schema.StringAttribute{
+											MarkdownDescription: fmt.Sprintf("Cloud provider name, one of %s", strings.Join(MKECloudProviders, ", ")),
+											Required:            true,
+											Validators: []validator.String{
+												stringvalidator.OneOf(MKECloudProviders...),
+											},
+										},
+										"config_file": schema.StringAttribute{
+											MarkdownDescription: "Path to the cloud provider config file, only for the azure and openstack providers",
+											Optional:            true,
+											Validators: []validator.String{
+												stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("config_data")),
+											},
+										},
+										"config_data": schema.StringAttribute{
+											MarkdownDescription: "Cloud provider config, only for the azure and openstack providers",
+											Optional:            true,
+											Sensitive:           true,
+										},
+									},
+								},
+							},
+						},
+					},
+
+					"msr": schema.ListNestedBlock{
+						MarkdownDescription: "MSR installation configuration",
+
+						Validators: []validator.List{
+							listvalidator.SizeAtMost(1),
+						},
+						NestedObject: schema.NestedBlockObject{
+							Attributes: map[string]schema.Attribute{
+								"version": schema.StringAttribute{
+									MarkdownDescription: "MCR version to install",
+									Required:            true,
+								},
+								"image_repo": schema.StringAttribute{
+									MarkdownDescription: "Image repo for MSR images",
+									Optional:            true,
+									Computed:            true,
+									Default:             stringdefault.StaticString("docker.io/mirantis"),
+								},
+								"replica_ids": schema.StringAttribute{
+									MarkdownDescription: "MSR replica IDs as a string",
+									Optional:            true,
+									Computed:            true,
+									Default:             stringdefault.StaticString("admin"),
+								},
+								"ca_cert_data": schema.StringAttribute{
+									MarkdownDescription: "MSR CA certificate (PEM)",
+									Optional:            true,
+								},
+								"cert_data": schema.StringAttribute{
+									MarkdownDescription: "MSR TLS certificate (PEM)",
+									Optional:            true,
+									Validators: []validator.String{
+										stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("key_data")),
+									},
+								},
+								"key_data": schema.StringAttribute{
+									MarkdownDescription: "MSR TLS private key (PEM)",
+									Optional:            true,
+									Sensitive:           true,
+									Validators: []validator.String{
+										stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("cert_data")),
+									},
+								},
+
+								"install_flags": schema.ListAttribute{
+									MarkdownDescription: "Optional MSR bootstrapper install flags, which are only used when MSR is installed",
+									ElementType:         types.StringType,
+									Optional:            true,
+									Computed:            true,
+									Default:             listdefault.StaticValue(types.ListNull(types.StringType)),
+								},
+								"upgrade_flags": schema.ListAttribute{
+									MarkdownDescription: "Optional MSR bootstrapper update flags",
+									ElementType:         types.StringType,
+									Optional:            true,
+									Computed:            true,
+									Default:             listdefault.StaticValue(types.ListNull(types.StringType)),
+								},
+							},
+						},
+					},
+
+					"host": schema.ListNestedBlock{
+						MarkdownDescription: "Individual host configuration, for each machine in the cluster",
+
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(1),
+						},
+
+						NestedObject: schema.NestedBlockObject{
+							Attributes: map[string]schema.Attribute{
+								"role": schema.StringAttribute{
+									MarkdownDescription: "Host machine role in the cluster",
+									Required:            true,
+								},
+								"environment": schema.MapAttribute{
+									MarkdownDescription: "Environment variables to set on the host, such as HTTP_PROXY, which launchpad writes to the host environment before installing MCR",
+									ElementType:         types.StringType,
+									Optional:            true,
+								},
+								"image_dir": schema.StringAttribute{
+									MarkdownDescription: "Local directory of image bundles (docker save tarballs), on the machine running terraform, which launchpad uploads and loads on the host before installing MKE",
+									Optional:            true,
+								},
+								"daemon_config": schema.StringAttribute{
+									MarkdownDescription: "MCR daemon.json (JSON object, such as from jsonencode) for the host, merged over the spec.mcr daemon_config. Launchpad writes the whole daemon.json from it, after adding the keys of the existing daemon.json which it does not set. So removing keys, or the whole daemon_config, does not remove them from the host, and is not a change that launchpad runs for",
+									Optional:            true,
+								},
+								"private_interface": schema.StringAttribute{
+									MarkdownDescription: "Network interface for the host private address, which swarm and MKE use for cluster traffic. Launchpad picks one if not set",
+									Optional:            true,
+									Validators: []validator.String{
+										stringvalidator.LengthAtLeast(3),
+									},
+								},
+							},
+							Blocks: map[string]schema.Block{
+
+								"hooks": schema.ListNestedBlock{
+									MarkdownDescription: "Hook configuration for the host, for the launchpad apply and reset operations",
+
+									Validators: []validator.List{
+										listvalidator.SizeAtMost(1),
+									},
+
+									NestedObject: schema.NestedBlockObject{
+										Attributes: map[string]schema.Attribute{},
+										Blocks: map[string]schema.Block{
+
+											"apply": schema.ListNestedBlock{
+												MarkdownDescription: "Launchpad.Apply string hooks for the host",
+
+												Validators: []validator.List{
+													listvalidator.SizeAtMost(1),
+												},
+
+												NestedObject: schema.NestedBlockObject{
+													Attributes: map[string]schema.Attribute{
+														"before": schema.ListAttribute{
+															MarkdownDescription: "String hooks to run on hosts before the Apply operation is run.",
+															ElementType:         types.StringType,
+															Optional:            true,
+															Computed:            true,
+															Default:             listdefault.StaticValue(types.ListNull(types.StringType)),
+														},
+														"after": schema.ListAttribute{
+															MarkdownDescription: "String hooks to run on hosts after the Apply operation is run.",
+															ElementType:         types.StringType,
+															Optional:            true,
+															Computed:            true,
+															Default:             listdefault.StaticValue(types.ListNull(types.StringType)),
+														},
+													},
+												},
+											},
+
+											"reset": schema.ListNestedBlock{
+												MarkdownDescription: "Launchpad.Reset string hooks for the host, which run when the cluster is destroyed, or when the host is removed from the spec with spec.cluster.reset_removed_hosts",
+
+												Validators: []validator.List{
+													listvalidator.SizeAtMost(1),
+												},
+
+												NestedObject: schema.NestedBlockObject{
+													Attributes: map[string]schema.Attribute{
+														"before": schema.ListAttribute{
+															MarkdownDescription: "String hooks to run on hosts before the Reset operation is run, e.g. to unmount volumes or deregister agents.",
+															ElementType:         types.StringType,
+															Optional:            true,
+															Computed:            true,
+															Default:             listdefault.StaticValue(types.ListNull(types.StringType)),
+														},
+														"after": schema.ListAttribute{
+															MarkdownDescription: "String hooks to run on hosts after the Reset operation is run.",
+															ElementType:         types.StringType,
+															Optional:            true,
+															Computed:            true,
+															Default:             listdefault.StaticValue(types.ListNull(types.StringType)),
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+
+								"ssh": schema.ListNestedBlock{
+									MarkdownDescription: "SSH configuration for the host",
+
+									NestedObject: schema.NestedBlockObject{
+										Attributes: map[string]schema.Attribute{
+											"address": schema.StringAttribute{
+												MarkdownDescription: "SSH endpoint",
+												Required:            true,
+											},
+											"key_path": schema.StringAttribute{
+												MarkdownDescription: "SSH private key path, defaults to the provider ssh_key_path",
+												Optional:            true,
+												Validators: []validator.String{
+													stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("private_key")),
+												},
+											},
+											"private_key": schema.StringAttribute{
+												MarkdownDescription: "SSH private key (PEM), such as a tls_private_key private_key_openssh, instead of a key_path. It is written to a temporary file (0600) for every launchpad run",
+												Optional:            true,
+												Sensitive:           true,
+											},
+											"host_key": schema.StringAttribute{
+												MarkdownDescription: "Pinned SSH host key, as an authorized_keys line or an ssh-keyscan line, which is verified before launchpad connects. Defaults to the provider known_hosts_file",
+												Optional:            true,
+											},
+											"use_agent": schema.BoolAttribute{
+												MarkdownDescription: "Authenticate with the keys from the SSH agent (SSH_AUTH_SOCK), instead of a key_path or private_key. The default identity files are also tried",
+												Optional:            true,
+												Computed:            true,
+												Default:             booldefault.StaticBool(false),
+											},
+											"user": schema.StringAttribute{
+												MarkdownDescription: "SSH user, defaults to the provider ssh_user",
+												Optional:            true,
+											},
+											"port": schema.Int64Attribute{
+												MarkdownDescription: "SSH Port, defaults to the provider ssh_port or 22",
+												Optional:            true,
+												Computed:            true,
+												PlanModifiers: []planmodifier.Int64{
+													int64planmodifier.UseStateForUnknown(),
+												},
+											},
+										},
+
+										Blocks: map[string]schema.Block{
+											"bastion": schema.ListNestedBlock{
+												MarkdownDescription: "SSH bastion (jump host) to reach the host through, defaults to the provider ssh_bastion",
+
+												Validators: []validator.List{
+													listvalidator.SizeAtMost(1),
+												},
+												NestedObject: schema.NestedBlockObject{
+													Attributes: map[string]schema.Attribute{
+														"address": schema.StringAttribute{
+															MarkdownDescription: "Bastion SSH endpoint",
+															Required:            true,
+														},
+														"user": schema.StringAttribute{
+															MarkdownDescription: "Bastion SSH user, defaults to the provider ssh_user",
+															Optional:            true,
+														},
+														"port": schema.Int64Attribute{
+															MarkdownDescription: "Bastion SSH port, defaults to 22",
+															Optional:            true,
+															Validators: []validator.Int64{
+																int64validator.Between(1, 65535),
+															},
+														},
+														"key_path": schema.StringAttribute{
+															MarkdownDescription: "Bastion SSH private key path, defaults to the provider ssh_key_path",
+															Optional:            true,
+															Validators: []validator.String{
+																stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("private_key")),
+															},
+														},
+														"private_key": schema.StringAttribute{
+															MarkdownDescription: "Bastion SSH private key (PEM), instead of a key_path",
+															Optional:            true,
+															Sensitive:           true,
+														},
+														"host_key": schema.StringAttribute{
+															MarkdownDescription: "Pinned bastion SSH host key, as an authorized_keys line or an ssh-keyscan line, which is verified before launchpad connects. Defaults to the provider known_hosts_file",
+															Optional:            true,
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+								"winrm": schema.ListNestedBlock{
+									MarkdownDescription: "WinRM configuration for the host",
+
+									NestedObject: schema.NestedBlockObject{
+										Attributes: map[string]schema.Attribute{
+											"address": schema.StringAttribute{
+												MarkdownDescription: "WinRM endpoint",
+												Required:            true,
+											},
+											"user": schema.StringAttribute{
+												MarkdownDescription: "WinRM user, defaults to the provider winrm_user",
+												Optional:            true,
+											},
+											"password": schema.StringAttribute{
+												MarkdownDescription: "WinRM password, defaults to the provider winrm_password",
+												Optional:            true,
+												Sensitive:           true,
+											},
+											"port": schema.Int64Attribute{
+												MarkdownDescription: "WinRM Port",
+												Optional:            true,
+												Computed:            true,
+												Default:             int64default.StaticInt64(5985),
+											},
+											"use_https": schema.BoolAttribute{
+												MarkdownDescription: "If false, then no HTTP is used for winrm transport",
+												Optional:            true,
+												Computed:            true,
+												Default:             booldefault.StaticBool(true),
+											},
+											"insecure": schema.BoolAttribute{
+												MarkdownDescription: "If true, then the WinRM HTTPS certificate is not verified",
+												Optional:            true,
+												Computed:            true,
+												Default:             booldefault.StaticBool(false),
+											},
+											"ca_cert_path": schema.StringAttribute{
+												MarkdownDescription: "CA certificate (PEM) path, which the WinRM HTTPS certificate is verified against",
+												Optional:            true,
+												Validators: []validator.String{
+													stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("ca_cert_data")),
+												},
+											},
+											"ca_cert_data": schema.StringAttribute{
+												MarkdownDescription: "CA certificate (PEM), instead of a ca_cert_path",
+												Optional:            true,
+											},
+											"cert_path": schema.StringAttribute{
+												MarkdownDescription: "Client certificate (PEM) path, for WinRM certificate authentication",
+												Optional:            true,
+												Validators: []validator.String{
+													stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("cert_data")),
+												},
+											},
+											"cert_data": schema.StringAttribute{
+												MarkdownDescription: "Client certificate (PEM), instead of a cert_path",
+												Optional:            true,
+											},
+											"key_path": schema.StringAttribute{
+												MarkdownDescription: "Client certificate private key (PEM) path",
+												Optional:            true,
+												Validators: []validator.String{
+													stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("key_data")),
+												},
+											},
+											"key_data": schema.StringAttribute{
+												MarkdownDescription: "Client certificate private key (PEM), instead of a key_path. Inline certificates and keys are written to temporary files (0600) for every launchpad run",
+												Optional:            true,
+												Sensitive:           true,
+											},
+											"tls_server_name": schema.StringAttribute{
+												MarkdownDescription: "Server name which the WinRM HTTPS certificate is verified for, if not the address",
+												Optional:            true,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
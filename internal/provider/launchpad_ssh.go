@@ -0,0 +1,314 @@
+package provider
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	k0s_rig "github.com/k0sproject/rig"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// launchpadSSHBastionModel an ssh bastion (jump host), as both the host ssh blocks and the provider configure it.
+type launchpadSSHBastionModel struct {
+	Address    types.String `tfsdk:"address"`
+	User       types.String `tfsdk:"user"`
+	Port       types.Int64  `tfsdk:"port"`
+	KeyPath    types.String `tfsdk:"key_path"`
+	PrivateKey types.String `tfsdk:"private_key"`
+	HostKey    types.String `tfsdk:"host_key"`
+}
+
+// SSH the rig connection for the bastion.
+//
+// A private key gets an empty key path, like a host private key, which writeConnectionFiles
+// replaces with a temporary key file. A host key which cannot be parsed is kept as it is, so
+// that rig refuses it, the plan has already reported it.
+func (b launchpadSSHBastionModel) SSH() *k0s_rig.SSH {
+	bssh := &k0s_rig.SSH{
+		Address: b.Address.ValueString(),
+		User:    b.User.ValueString(),
+		Port:    int(b.Port.ValueInt64()),
+		KeyPath: b.KeyPath.ValueStringPointer(),
+		HostKey: b.HostKey.ValueString(),
+	}
+	if b.PrivateKey.ValueString() != "" {
+		bssh.KeyPath = new(string)
+	}
+	if hostKey, err := launchpadHostKey(bssh.HostKey); bssh.HostKey != "" && err == nil {
+		bssh.HostKey = hostKey
+	}
+	return bssh
+}
+
+// writeConnectionFiles write the inline ssh private keys of the hosts and bastions, and the inline winrm certificates, to temporary files, and point the cluster config hosts at them.
+//
+// Rig only reads keys and certificates from files. The provider bastion is applied by ApplyHostDefaults, so
+// this has to run after it. The returned cleanup removes the files again, and has to be
+// called once launchpad is done with the cluster config, even when this fails.
+func writeConnectionFiles(ls launchpadModel, lpm *LaunchpadProviderModel, cc *mcc_mke_api.ClusterConfig) (func(), error) {
+	files := []string{}
+	cleanup := func() {
+		for _, f := range files {
+			os.Remove(f)
+		}
+	}
+
+	write := func(key string) (*string, error) {
+		f, err := os.CreateTemp("", "launchpad-key-*")
+		if err != nil {
+			return nil, fmt.Errorf("could not create a temporary key file: %w", err)
+		}
+		files = append(files, f.Name())
+
+		// CreateTemp already uses 0600, but ssh refuses keys which anyone else can read, so make sure
+		if err := f.Chmod(0600); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("could not protect the temporary key file: %w", err)
+		}
+		if _, err := f.WriteString(key); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("could not write the temporary key file: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return nil, fmt.Errorf("could not write the temporary key file: %w", err)
+		}
+
+		keyPath := f.Name()
+		return &keyPath, nil
+	}
+
+	// the provider bastion key is shared by all of the hosts that use the provider bastion
+	var providerBastionKey *string
+
+	for i, host := range ls.Spec.Hosts {
+		if len(host.WinRM) > 0 && i < len(cc.Spec.Hosts) && cc.Spec.Hosts[i].WinRM != nil {
+			hwinrm, mccWinRM := host.WinRM[0], cc.Spec.Hosts[i].WinRM
+
+			for _, f := range []struct {
+				data string
+				path *string
+			}{
+				{data: hwinrm.CACertData.ValueString(), path: &mccWinRM.CACertPath},
+				{data: hwinrm.CertData.ValueString(), path: &mccWinRM.CertPath},
+				{data: hwinrm.KeyData.ValueString(), path: &mccWinRM.KeyPath},
+			} {
+				if f.data == "" {
+					continue
+				}
+				p, err := write(f.data)
+				if err != nil {
+					return cleanup, err
+				}
+				*f.path = *p
+			}
+		}
+
+		if len(host.SSH) == 0 || i >= len(cc.Spec.Hosts) || cc.Spec.Hosts[i].SSH == nil {
+			continue
+		}
+		hssh, mccSSH := host.SSH[0], cc.Spec.Hosts[i].SSH
+
+		if key := hssh.PrivateKey.ValueString(); key != "" {
+			keyPath, err := write(key)
+			if err != nil {
+				return cleanup, err
+			}
+			mccSSH.KeyPath = keyPath
+		}
+
+		if mccSSH.Bastion == nil {
+			continue
+		}
+		if len(hssh.Bastion) > 0 {
+			if key := hssh.Bastion[0].PrivateKey.ValueString(); key != "" {
+				keyPath, err := write(key)
+				if err != nil {
+					return cleanup, err
+				}
+				mccSSH.Bastion.KeyPath = keyPath
+			}
+		} else if lpm != nil && len(lpm.SSHBastion) > 0 {
+			if key := lpm.SSHBastion[0].PrivateKey.ValueString(); key != "" {
+				if providerBastionKey == nil {
+					keyPath, err := write(key)
+					if err != nil {
+						return cleanup, err
+					}
+					providerBastionKey = keyPath
+				}
+				mccSSH.Bastion.KeyPath = providerBastionKey
+			}
+		}
+	}
+
+	return cleanup, nil
+}
+
+// errHostKeyVerified ends the ssh handshake of a host key check, before any credentials are sent.
+var errHostKeyVerified = errors.New("host key verified")
+
+// launchpadHostKey normalise a host key into the "type base64" form which rig compares host keys in.
+//
+// The key can be an authorized_keys line, or a known_hosts line such as ssh-keyscan prints.
+func launchpadHostKey(key string) (string, error) {
+	pk, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+	if err != nil {
+		if _, _, pk, _, _, err = ssh.ParseKnownHosts([]byte(key)); err != nil {
+			return "", fmt.Errorf("could not parse the host key: %w", err)
+		}
+	}
+	return launchpadSSHKeyString(pk), nil
+}
+
+// verifySSHHostKeys check the host keys of the ssh hosts and their bastions against their host_key, or else the known hosts file, before launchpad connects to them.
+//
+// Only the ssh key exchange is done, no credentials are sent. A host which is not in the
+// known hosts file fails. The verified keys are pinned as the rig host keys, so that rig
+// neither reads nor appends to a known hosts file itself. Hosts behind a bastion cannot be
+// reached directly, so rig checks their host_key, and without one they fail when there is
+// a known hosts file. The errors are per host address.
+func verifySSHHostKeys(cc *mcc_mke_api.ClusterConfig, knownHostsFile string, timeout time.Duration) map[string]error {
+	errs := map[string]error{}
+
+	var knownHosts ssh.HostKeyCallback
+	if knownHostsFile != "" {
+		kh, err := knownhosts.New(knownHostsFile)
+		if err != nil {
+			for _, h := range cc.Spec.Hosts {
+				if h.SSH != nil {
+					errs[h.Address()] = fmt.Errorf("could not read the known hosts file: %w", err)
+				}
+			}
+			return errs
+		}
+		knownHosts = kh
+	}
+
+	// a provider bastion is shared by the hosts, so it is only dialed once
+	verified := map[string]string{}
+	failed := map[string]error{}
+
+	verify := func(c *k0s_rig.SSH) error {
+		address := net.JoinHostPort(c.Address, strconv.Itoa(c.Port))
+		if err, ok := failed[address]; ok {
+			return err
+		}
+		if key, ok := verified[address]; ok && (c.HostKey == "" || c.HostKey == key) {
+			c.HostKey = key
+			return nil
+		}
+
+		var verifyErr error
+		var verifiedKey string
+		callback := func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			if c.HostKey != "" {
+				if launchpadSSHKeyString(key) != c.HostKey {
+					verifyErr = fmt.Errorf("the host key %s does not match the host_key", ssh.FingerprintSHA256(key))
+					return verifyErr
+				}
+			} else if err := knownHosts(hostname, remote, key); err != nil {
+				var keyErr *knownhosts.KeyError
+				if errors.As(err, &keyErr) && len(keyErr.Want) == 0 {
+					verifyErr = fmt.Errorf("the host key %s is not in %s", ssh.FingerprintSHA256(key), knownHostsFile)
+				} else {
+					verifyErr = fmt.Errorf("the host key %s does not match %s: %w", ssh.FingerprintSHA256(key), knownHostsFile, err)
+				}
+				return verifyErr
+			}
+
+			verifiedKey = launchpadSSHKeyString(key)
+			return errHostKeyVerified
+		}
+
+		_, err := ssh.Dial("tcp", address, &ssh.ClientConfig{User: c.User, HostKeyCallback: callback, Timeout: timeout})
+		switch {
+		case verifiedKey != "":
+			verified[address] = verifiedKey
+			c.HostKey = verifiedKey
+			return nil
+		case verifyErr != nil:
+			err = verifyErr
+		default:
+			err = fmt.Errorf("could not verify the host key: %w", err)
+		}
+		failed[address] = err
+		return err
+	}
+
+	for _, h := range cc.Spec.Hosts {
+		hssh := h.SSH
+		if hssh == nil {
+			continue
+		}
+
+		if b := hssh.Bastion; b != nil {
+			if b.HostKey != "" || knownHosts != nil {
+				if err := verify(b); err != nil {
+					errs[h.Address()] = fmt.Errorf("bastion %s: %w", b.Address, err)
+					continue
+				}
+			}
+			if hssh.HostKey == "" && knownHosts != nil {
+				errs[h.Address()] = fmt.Errorf("the host is behind a bastion, so it cannot be checked against %s, set its host_key", knownHostsFile)
+			}
+			continue
+		}
+
+		if hssh.HostKey == "" && knownHosts == nil {
+			continue
+		}
+		if err := verify(hssh); err != nil {
+			errs[h.Address()] = err
+		}
+	}
+
+	return errs
+}
+
+// launchpadSSHKeyString a public key in the "type base64" form which rig compares host keys in.
+func launchpadSSHKeyString(key ssh.PublicKey) string {
+	return key.Type() + " " + base64.StdEncoding.EncodeToString(key.Marshal())
+}
+
+// launchpadSSHAuthCheck refuse hosts which ask for the ssh agent and also set a key, and return the path of the use_agent attribute.
+func launchpadSSHAuthCheck(pls launchpadModel) (path.Path, error) {
+	for i, h := range pls.Spec.Hosts {
+		for j, hssh := range h.SSH {
+			if hssh.UseAgent.ValueBool() && (hssh.KeyPath.ValueString() != "" || hssh.PrivateKey.ValueString() != "") {
+				return launchpadHostPath(i).AtName("ssh").AtListIndex(j).AtName("use_agent"), fmt.Errorf("host %s sets use_agent, and also a key_path or private_key", hssh.Address.ValueString())
+			}
+		}
+	}
+	return path.Empty(), nil
+}
+
+// launchpadHostKeyCheck refuse host and bastion host keys which cannot be parsed, and return the path of the host_key attribute.
+func launchpadHostKeyCheck(pls launchpadModel) (path.Path, error) {
+	for i, h := range pls.Spec.Hosts {
+		for j, hssh := range h.SSH {
+			sshPath := launchpadHostPath(i).AtName("ssh").AtListIndex(j)
+			if hk := hssh.HostKey.ValueString(); hk != "" {
+				if _, err := launchpadHostKey(hk); err != nil {
+					return sshPath.AtName("host_key"), fmt.Errorf("host %s: %w", hssh.Address.ValueString(), err)
+				}
+			}
+			for k, b := range hssh.Bastion {
+				if hk := b.HostKey.ValueString(); hk != "" {
+					if _, err := launchpadHostKey(hk); err != nil {
+						return sshPath.AtName("bastion").AtListIndex(k).AtName("host_key"), fmt.Errorf("host %s bastion: %w", hssh.Address.ValueString(), err)
+					}
+				}
+			}
+		}
+	}
+	return path.Empty(), nil
+}
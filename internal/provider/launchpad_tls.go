@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	mcc_common_api "github.com/Mirantis/mcc/pkg/product/common/api"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// launchpadFileData the contents of either the inline data, or the file at the path.
+//
+// Launchpad only reads the path attributes when it loads a yaml config, so the provider has
+// to load the files itself.
+func launchpadFileData(data, path types.String) (string, error) {
+	if p := path.ValueString(); p != "" {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return "", fmt.Errorf("could not read %s: %w", p, err)
+		}
+		return string(b), nil
+	}
+	return data.ValueString(), nil
+}
+
+// launchpadMKETLSNames the names which the MKE TLS certificate has to be valid for.
+//
+// The --san install flags are the names that MKE is reached through, such as a load
+// balancer, so if there are any then the certificate only has to cover one of them.
+// Otherwise it has to cover every manager address.
+func launchpadMKETLSNames(ls launchpadModel) ([]string, bool) {
+	var flags []string
+	if !ls.Spec.MKE.InstallFlags.IsNull() && !ls.Spec.MKE.InstallFlags.IsUnknown() {
+		ls.Spec.MKE.InstallFlags.ElementsAs(context.Background(), &flags, true)
+	}
+
+	sans := []string{}
+	for _, f := range flags {
+		if strings.HasPrefix(f, "--san=") || strings.HasPrefix(f, "--san ") {
+			sans = append(sans, mcc_common_api.FlagValue(f))
+		}
+	}
+	if len(sans) > 0 {
+		return sans, true
+	}
+
+	managers := []string{}
+	for _, h := range ls.Spec.Hosts {
+		if h.Role.ValueString() == "manager" {
+			managers = append(managers, h.Address())
+		}
+	}
+	return managers, false
+}
+
+// launchpadMKETLSCheck check that the MKE TLS certificates parse, that the key matches the certificate, and that the certificate is valid for MKE.
+//
+// The certificates are read from the model, so this only loads the certificate files and not
+// the rest of the cluster config.
+func launchpadMKETLSCheck(ls launchpadModel) error {
+	mke := ls.Spec.MKE
+	caCert, err := launchpadFileData(mke.CACertData, mke.CACertPath)
+	if err != nil {
+		return err
+	}
+	certData, err := launchpadFileData(mke.CertData, mke.CertPath)
+	if err != nil {
+		return err
+	}
+	keyData, err := launchpadFileData(mke.KeyData, mke.KeyPath)
+	if err != nil {
+		return err
+	}
+
+	if caCert == "" && certData == "" && keyData == "" {
+		return nil
+	}
+
+	if certData == "" || keyData == "" {
+		return fmt.Errorf("an MKE TLS certificate needs both a certificate and a key")
+	}
+
+	if caCert != "" {
+		if _, err := launchpadParseCertificate(caCert); err != nil {
+			return fmt.Errorf("the MKE CA certificate is invalid: %w", err)
+		}
+	}
+
+	cert, err := launchpadParseCertificate(certData)
+	if err != nil {
+		return fmt.Errorf("the MKE TLS certificate is invalid: %w", err)
+	}
+	if _, err := tls.X509KeyPair([]byte(certData), []byte(keyData)); err != nil {
+		return fmt.Errorf("the MKE TLS key does not match the certificate: %w", err)
+	}
+
+	names, external := launchpadMKETLSNames(ls)
+	missing := []string{}
+	for _, name := range names {
+		if err := cert.VerifyHostname(name); err != nil {
+			missing = append(missing, name)
+		}
+	}
+	if external && len(missing) == len(names) {
+		return fmt.Errorf("the MKE TLS certificate SANs include none of the --san install flags: %s", strings.Join(missing, ", "))
+	}
+	if !external && len(missing) > 0 {
+		return fmt.Errorf("the MKE TLS certificate SANs do not include the manager addresses: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// launchpadParseCertificate parse the first certificate of a PEM bundle.
+func launchpadParseCertificate(data string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	if block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("expected a CERTIFICATE PEM block, found %s", block.Type)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
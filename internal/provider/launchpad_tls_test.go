@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// testLaunchpadTLSCertificate a self signed certificate (PEM) for the names, and its key (PEM).
+func testLaunchpadTLSCertificate(t *testing.T, names ...string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate a key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mke"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     names,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create a certificate: %s", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("could not marshal the key: %s", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+func TestLaunchpadMKETLSCheck(t *testing.T) {
+	cert, key := testLaunchpadTLSCertificate(t, "manager1.example.org")
+	lbCert, lbKey := testLaunchpadTLSCertificate(t, "*.lb.example.org")
+	_, otherKey := testLaunchpadTLSCertificate(t, "manager1.example.org")
+
+	tests := []struct {
+		name    string
+		change  func(mke *launchpadModelSpecMKE)
+		invalid bool
+	}{
+		{
+			name:   "no certificates",
+			change: func(mke *launchpadModelSpecMKE) {},
+		},
+		{
+			name: "manager address",
+			change: func(mke *launchpadModelSpecMKE) {
+				mke.CACertData = types.StringValue(cert)
+				mke.CertData = types.StringValue(cert)
+				mke.KeyData = types.StringValue(key)
+			},
+		},
+		{
+			name: "san install flag",
+			change: func(mke *launchpadModelSpecMKE) {
+				mke.CertData = types.StringValue(lbCert)
+				mke.KeyData = types.StringValue(lbKey)
+				mke.InstallFlags = testStringList("--san=mke.lb.example.org", "--san=10.0.0.1")
+			},
+		},
+		{
+			name: "missing manager address",
+			change: func(mke *launchpadModelSpecMKE) {
+				mke.CertData = types.StringValue(lbCert)
+				mke.KeyData = types.StringValue(lbKey)
+			},
+			invalid: true,
+		},
+		{
+			name: "missing san install flag",
+			change: func(mke *launchpadModelSpecMKE) {
+				mke.CertData = types.StringValue(cert)
+				mke.KeyData = types.StringValue(key)
+				mke.InstallFlags = testStringList("--san mke.lb.example.org")
+			},
+			invalid: true,
+		},
+		{
+			name: "certificate without key",
+			change: func(mke *launchpadModelSpecMKE) {
+				mke.CertData = types.StringValue(cert)
+			},
+			invalid: true,
+		},
+		{
+			name: "key mismatch",
+			change: func(mke *launchpadModelSpecMKE) {
+				mke.CertData = types.StringValue(cert)
+				mke.KeyData = types.StringValue(otherKey)
+			},
+			invalid: true,
+		},
+		{
+			name: "invalid ca",
+			change: func(mke *launchpadModelSpecMKE) {
+				mke.CACertData = types.StringValue("not a certificate")
+				mke.CertData = types.StringValue(cert)
+				mke.KeyData = types.StringValue(key)
+			},
+			invalid: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ls := testLaunchpadModel()
+			test.change(&ls.Spec.MKE)
+
+			err := launchpadMKETLSCheck(ls)
+			if test.invalid && err == nil {
+				t.Error("expected the certificates to be invalid")
+			}
+			if !test.invalid && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestLaunchpadModelClusterConfigTLSPaths(t *testing.T) {
+	cert, key := testLaunchpadTLSCertificate(t, "manager1.example.org")
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certPath, []byte(cert), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, []byte(key), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ls := testLaunchpadModel()
+	ls.Spec.MKE.CertPath = types.StringValue(certPath)
+	ls.Spec.MKE.KeyPath = types.StringValue(keyPath)
+	if !ls.HasMKETLS() {
+		t.Error("expected MKE TLS certificates")
+	}
+
+	cc, err := ls.ClusterConfig(&diag.Diagnostics{})
+	if err != nil {
+		t.Fatalf("unexpected cluster config error: %s", err)
+	}
+	if cc.Spec.MKE.CertData != cert || cc.Spec.MKE.KeyData != key {
+		t.Error("expected the certificate files to be loaded")
+	}
+	if err := launchpadMKETLSCheck(ls); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	ls.Spec.MKE.KeyPath = types.StringValue(filepath.Join(dir, "missing.pem"))
+	if _, err := ls.ClusterConfig(&diag.Diagnostics{}); err == nil {
+		t.Error("expected an error for a missing key file")
+	}
+	if err := launchpadMKETLSCheck(ls); err == nil {
+		t.Error("expected the check to fail for a missing key file")
+	}
+
+	ls.Spec.MKE.KeyPath = types.StringUnknown()
+	if ls.HasMKETLS() {
+		t.Error("did not expect unknown MKE TLS certificates to be validated")
+	}
+}
@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// launchpadModelFromSchema14 upgrade a state object of the original schema, the fields which it did not have get their defaults.
+func launchpadModelFromSchema14(ls14 launchpadSchema14Model) launchpadModel {
+	ls := launchpadModel{
+		Id:               ls14.Id,
+		SkipDestroy:      ls14.SkipDestroy,
+		ApplyConcurrency: types.Int64Null(),
+		Force:            types.BoolValue(false),
+		DisableCleanup:   types.BoolValue(false),
+		LogFile:          types.StringNull(),
+		PlannedActions:   types.ListValueMust(types.StringType, []attr.Value{}),
+
+		Metadata: launchpadModelMetadata{
+			Name: ls14.Metadata.Name,
+		},
+
+		Spec: launchpadModelSpec{
+			Cluster: []launchpadModelCluster{},
+			Airgap:  []launchpadModelSpecAirgap{},
+
+			MCR: launchpadModelSpecMCR{
+				Version:           ls14.Spec.MCR.Version,
+				Channel:           ls14.Spec.MCR.Channel,
+				InstallURLLinux:   ls14.Spec.MCR.InstallURLLinux,
+				InstallURLWindows: ls14.Spec.MCR.InstallURLWindows,
+				RepoURL:           ls14.Spec.MCR.RepoURL,
+				DaemonConfig:      types.StringNull(),
+			},
+
+			MKE: launchpadModelSpecMKE{
+				AdminPassword:       ls14.Spec.MKE.AdminPassword,
+				AdminUsername:       ls14.Spec.MKE.AdminUsername,
+				ImageRepo:           ls14.Spec.MKE.ImageRepo,
+				Version:             ls14.Spec.MKE.Version,
+				InstallFlags:        ls14.Spec.MKE.InstallFlags,
+				UpgradeFlags:        ls14.Spec.MKE.UpgradeFlags,
+				SwarmInstallFlags:   types.ListNull(types.StringType),
+				SwarmUpdateCommands: types.ListNull(types.StringType),
+				LicenseFilePath:     ls14.Spec.MKE.LicenseFilePath,
+				ConfigData:          types.StringNull(),
+				CACertPath:          types.StringNull(),
+				CertPath:            types.StringNull(),
+				KeyPath:             types.StringNull(),
+				CACertData:          types.StringNull(),
+				CertData:            types.StringNull(),
+				KeyData:             types.StringNull(),
+
+				CloudProvider: []launchpadModelSpecMKECloudProvider{},
+			},
+
+			MSR:   []launchpadModelSpecMSR{},
+			Hosts: []launchpadModelSpecHost{},
+		},
+	}
+
+	for _, c := range ls14.Spec.Cluster {
+		ls.Spec.Cluster = append(ls.Spec.Cluster, launchpadModelCluster{
+			Prune:             c.Prune,
+			ResetRemovedHosts: types.BoolValue(false),
+		})
+	}
+
+	for _, msr := range ls14.Spec.MSR {
+		ls.Spec.MSR = append(ls.Spec.MSR, launchpadModelSpecMSR{
+			ImageRepo:    msr.ImageRepo,
+			Version:      msr.Version,
+			ReplicaIDs:   msr.ReplicaIDs,
+			CACertData:   types.StringNull(),
+			CertData:     types.StringNull(),
+			KeyData:      types.StringNull(),
+			InstallFlags: msr.InstallFlags,
+			UpgradeFlags: msr.UpgradeFlags,
+		})
+	}
+
+	for _, host14 := range ls14.Spec.Hosts {
+		host := launchpadModelSpecHost{
+			Role:             host14.Role,
+			Environment:      types.MapNull(types.StringType),
+			ImageDir:         types.StringNull(),
+			PrivateInterface: types.StringNull(),
+			DaemonConfig:     types.StringNull(),
+			Hooks:            []launchpadModelSpecHostHooks{},
+			SSH:              []launchpadModelSpecHostSSH{},
+			WinRM:            []launchpadModelSpecHostWinrm{},
+		}
+
+		for _, hooks := range host14.Hooks {
+			hh := launchpadModelSpecHostHooks{
+				Apply: []launchpadModelSpecHostHookAction{},
+				Reset: []launchpadModelSpecHostHookAction{},
+			}
+			for _, ha := range hooks.Apply {
+				hh.Apply = append(hh.Apply, launchpadModelSpecHostHookAction{
+					Before: ha.Before,
+					After:  ha.After,
+				})
+			}
+			host.Hooks = append(host.Hooks, hh)
+		}
+
+		for _, hssh := range host14.SSH {
+			host.SSH = append(host.SSH, launchpadModelSpecHostSSH{
+				Address:    hssh.Address,
+				KeyPath:    hssh.KeyPath,
+				PrivateKey: types.StringNull(),
+				UseAgent:   types.BoolValue(false),
+				HostKey:    types.StringNull(),
+				User:       hssh.User,
+				Port:       hssh.Port,
+				Bastion:    []launchpadSSHBastionModel{},
+			})
+		}
+
+		for _, hwinrm := range host14.WinRM {
+			host.WinRM = append(host.WinRM, launchpadModelSpecHostWinrm{
+				Address:  hwinrm.Address,
+				User:     hwinrm.User,
+				Password: hwinrm.Password,
+				Port:     hwinrm.Port,
+				UseHTTPS: hwinrm.UseHTTPS,
+				Insecure: hwinrm.Insecure,
+
+				CACertPath:    types.StringNull(),
+				CACertData:    types.StringNull(),
+				CertPath:      types.StringNull(),
+				CertData:      types.StringNull(),
+				KeyPath:       types.StringNull(),
+				KeyData:       types.StringNull(),
+				TLSServerName: types.StringNull(),
+			})
+		}
+
+		ls.Spec.Hosts = append(ls.Spec.Hosts, host)
+	}
+
+	return ls
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestLaunchpadConfigResourceUpgradeState(t *testing.T) {
+	ctx := context.Background()
+
+	ls14 := launchpadSchema14Model{
+		Id:          types.StringValue("test"),
+		SkipDestroy: types.BoolValue(false),
+		Metadata:    launchpadSchema14ModelMetadata{Name: types.StringValue("test")},
+		Spec: launchpadSchema14ModelSpec{
+			Cluster: []launchpadSchema14ModelCluster{{Prune: types.BoolValue(true)}},
+			MCR: launchpadSchema14ModelSpecMCR{
+				Version:           types.StringValue("23.0"),
+				Channel:           types.StringValue("stable"),
+				InstallURLLinux:   types.StringValue("https://get.mirantis.com/"),
+				InstallURLWindows: types.StringValue("https://get.mirantis.com/install.ps1"),
+				RepoURL:           types.StringValue("https://repos.mirantis.com"),
+			},
+			MKE: launchpadSchema14ModelSpecMKE{
+				AdminPassword:   types.StringValue("mypassword"),
+				AdminUsername:   types.StringValue("admin"),
+				ImageRepo:       types.StringValue("docker.io/mirantis"),
+				Version:         types.StringValue("3.6.4"),
+				InstallFlags:    types.ListValueMust(types.StringType, []attr.Value{types.StringValue("--flag1")}),
+				UpgradeFlags:    types.ListNull(types.StringType),
+				LicenseFilePath: types.StringValue(""),
+			},
+			MSR: []launchpadSchema14ModelSpecMSR{},
+			Hosts: []launchpadSchema14ModelSpecHost{
+				{
+					Role: types.StringValue("manager"),
+					Hooks: []launchpadSchema14ModelSpecHostHooks{
+						{Apply: []launchpadSchema14ModelSpecHostHookAction{{
+							Before: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ls -la")}),
+							After:  types.ListNull(types.StringType),
+						}}},
+					},
+					SSH: []launchpadSchema14ModelSpecHostSSH{{
+						Address: types.StringValue("manager1.example.org"),
+						KeyPath: types.StringValue("./key.pem"),
+						User:    types.StringValue("ubuntu"),
+						Port:    types.Int64Value(22),
+					}},
+					WinRM: []launchpadSchema14ModelSpecHostWinrm{},
+				},
+			},
+		},
+	}
+
+	schema14 := launchpadSchema14()
+	prior := tfsdk.State{Schema: schema14, Raw: tftypes.NewValue(schema14.Type().TerraformType(ctx), nil)}
+	if diags := prior.Set(ctx, ls14); diags.HasError() {
+		t.Fatalf("could not build the 1.4 state: %v", diags)
+	}
+
+	r := &LaunchpadConfigResource{}
+	upgrader, ok := r.UpgradeState(ctx)[0]
+	if !ok {
+		t.Fatal("expected a state upgrader for schema version 0")
+	}
+
+	schema := launchpadSchema()
+	resp := resource.UpgradeStateResponse{
+		State: tfsdk.State{Schema: schema, Raw: tftypes.NewValue(schema.Type().TerraformType(ctx), nil)},
+	}
+	upgrader.StateUpgrader(ctx, resource.UpgradeStateRequest{State: &prior}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("state upgrade failed: %v", resp.Diagnostics)
+	}
+
+	var ls launchpadModel
+	if diags := resp.State.Get(ctx, &ls); diags.HasError() {
+		t.Fatalf("could not read the upgraded state: %v", diags)
+	}
+
+	if !ls.ApplyConcurrency.IsNull() || !ls.LogFile.IsNull() {
+		t.Errorf("expected apply_concurrency and log_file to be unset, got %s and %s", ls.ApplyConcurrency, ls.LogFile)
+	}
+	if !ls.Force.Equal(types.BoolValue(false)) || !ls.DisableCleanup.Equal(types.BoolValue(false)) {
+		t.Errorf("expected force and disable_cleanup to default to false, got %s and %s", ls.Force, ls.DisableCleanup)
+	}
+	if v := ls.Spec.MKE.Version.ValueString(); v != "3.6.4" {
+		t.Errorf("expected the MKE version to be kept, got %s", v)
+	}
+	if !ls.Spec.MKE.ConfigData.IsNull() {
+		t.Errorf("expected the new config_data to be null, got %s", ls.Spec.MKE.ConfigData)
+	}
+	if len(ls.Spec.Cluster) != 1 || !ls.Spec.Cluster[0].Prune.ValueBool() {
+		t.Error("expected cluster prune to be kept")
+	}
+	if len(ls.Spec.Hosts) != 1 || ls.Spec.Hosts[0].SSH[0].Port.ValueInt64() != 22 {
+		t.Fatalf("expected the ssh host to be kept: %+v", ls.Spec.Hosts)
+	}
+	if v := ls.Spec.Hosts[0].Hooks[0].Apply[0].Before.Elements(); len(v) != 1 {
+		t.Errorf("expected the apply before hook to be kept, got %v", v)
+	}
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// launchpadWinRMCertCheck refuse winrm client certificates without a key, or keys without a certificate, and return the path of the winrm block.
+func launchpadWinRMCertCheck(pls launchpadModel) (path.Path, error) {
+	for i, h := range pls.Spec.Hosts {
+		for j, hwinrm := range h.WinRM {
+			cert := !hwinrm.CertPath.IsNull() || !hwinrm.CertData.IsNull()
+			key := !hwinrm.KeyPath.IsNull() || !hwinrm.KeyData.IsNull()
+			if cert != key {
+				return launchpadHostPath(i).AtName("winrm").AtListIndex(j), fmt.Errorf("host %s needs both a client certificate and a key for winrm certificate authentication", hwinrm.Address.ValueString())
+			}
+		}
+	}
+	return path.Empty(), nil
+}
+
+// launchpadWinRMInsecureHosts the winrm hosts which use https without verifying the certificate.
+func launchpadWinRMInsecureHosts(pls launchpadModel) []string {
+	hosts := []string{}
+	for _, h := range pls.Spec.Hosts {
+		for _, hwinrm := range h.WinRM {
+			if hwinrm.UseHTTPS.ValueBool() && hwinrm.Insecure.ValueBool() {
+				hosts = append(hosts, hwinrm.Address.ValueString())
+			}
+		}
+	}
+	return hosts
+}
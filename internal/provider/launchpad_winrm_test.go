@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestLaunchpadWinRMCertCheck(t *testing.T) {
+	pls := testLaunchpadDiffModel()
+	pls.Spec.Hosts[0].SSH = []launchpadModelSpecHostSSH{}
+	pls.Spec.Hosts[0].WinRM = []launchpadModelSpecHostWinrm{{
+		Address:  types.StringValue("windowsworker1.example.org"),
+		UseHTTPS: types.BoolValue(true),
+		Insecure: types.BoolValue(true),
+		CertPath: types.StringValue("./cert.pem"),
+	}}
+	if p, err := launchpadWinRMCertCheck(pls); err == nil {
+		t.Error("expected an error for a client certificate without a key")
+	} else if expected := path.Root("spec").AtName("host").AtListIndex(0).AtName("winrm").AtListIndex(0); !p.Equal(expected) {
+		t.Errorf("expected the error on %s, got %s", expected, p)
+	}
+	if insecure := launchpadWinRMInsecureHosts(pls); len(insecure) != 1 || insecure[0] != "windowsworker1.example.org" {
+		t.Errorf("expected the insecure https host, got %v", insecure)
+	}
+
+	pls.Spec.Hosts[0].WinRM[0].KeyData = types.StringValue("client key")
+	pls.Spec.Hosts[0].WinRM[0].Insecure = types.BoolValue(false)
+	if _, err := launchpadWinRMCertCheck(pls); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if insecure := launchpadWinRMInsecureHosts(pls); len(insecure) != 0 {
+		t.Errorf("did not expect insecure hosts, got %v", insecure)
+	}
+}
@@ -1,11 +1,7 @@
 package provider
 
 import (
-	"context"
-	"reflect"
-
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
-	"github.com/hashicorp/terraform-plugin-framework/diag"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
@@ -16,16 +12,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-
-	mcc_common_api "github.com/Mirantis/mcc/pkg/product/common/api"
-	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
-	k0s_rig "github.com/k0sproject/rig"
 )
 
 const (
 	HostRoleMSR = "msr"
 )
 
+// launchpadSchema14 the original (version 0) resource schema.
+//
+// It is no longer used for the resource, but is kept as the prior schema so that
+// existing states can be upgraded to the current schema.
 func launchpadSchema14() schema.Schema {
 	return schema.Schema{
 		// This description is used by the documentation generator and the language server.
@@ -349,155 +345,6 @@ type launchpadSchema14Model struct {
 	Spec     launchpadSchema14ModelSpec     `tfsdk:"spec"`
 }
 
-// ClusterEqual compare with another state, to see it they are different enough to warrant running launchpad.
-func (ls launchpadSchema14Model) ClusterEqual(c launchpadSchema14Model) bool {
-	return reflect.DeepEqual(ls.Spec, c.Spec)
-}
-
-// ClusterConfig convert this state object into a proper ClusterConfig.
-func (ls launchpadSchema14Model) ClusterConfig(diags diag.Diagnostics) (mcc_mke_api.ClusterConfig, error) {
-	cc := mcc_mke_api.ClusterConfig{
-		APIVersion: "launchpad.mirantis.com/mke/v1.4",
-		Kind:       "mke",
-
-		Metadata: func() *mcc_mke_api.ClusterMeta {
-			return &mcc_mke_api.ClusterMeta{
-				Name: ls.Metadata.Name.String(),
-			}
-		}(),
-
-		Spec: &mcc_mke_api.ClusterSpec{
-			Cluster: mcc_mke_api.Cluster{
-				Prune: false,
-			},
-
-			Hosts: mcc_mke_api.Hosts{},
-
-			MCR: mcc_common_api.MCRConfig{
-				Version:           ls.Spec.MCR.Version.ValueString(),
-				InstallURLLinux:   ls.Spec.MCR.InstallURLLinux.ValueString(),
-				InstallURLWindows: ls.Spec.MCR.InstallURLWindows.ValueString(),
-				RepoURL:           ls.Spec.MCR.RepoURL.ValueString(),
-				Channel:           ls.Spec.MCR.Channel.ValueString(),
-			},
-
-			MKE: mcc_mke_api.MKEConfig{
-				AdminUsername:   ls.Spec.MKE.AdminUsername.ValueString(),
-				AdminPassword:   ls.Spec.MKE.AdminPassword.ValueString(),
-				ImageRepo:       ls.Spec.MKE.ImageRepo.ValueString(),
-				Version:         ls.Spec.MKE.Version.ValueString(),
-				InstallFlags:    mcc_common_api.Flags{},
-				UpgradeFlags:    mcc_common_api.Flags{},
-				Metadata:        &mcc_mke_api.MKEMetadata{},
-				LicenseFilePath: ls.Spec.MKE.LicenseFilePath.ValueString(),
-			},
-
-			MSR: nil,
-		},
-	}
-
-	for _, msr := range ls.Spec.MSR {
-		hasMSRHosts := false
-		for _, host := range ls.Spec.Hosts {
-			if host.Role.ValueString() == HostRoleMSR {
-				hasMSRHosts = true
-			}
-		}
-		if hasMSRHosts {
-			cc.Spec.MSR = &mcc_mke_api.MSRConfig{
-				ImageRepo:    msr.ImageRepo.ValueString(),
-				Version:      msr.Version.ValueString(),
-				ReplicaIDs:   msr.ReplicaIDs.ValueString(),
-				InstallFlags: mcc_common_api.Flags{},
-				UpgradeFlags: mcc_common_api.Flags{},
-			}
-
-			if !msr.InstallFlags.IsNull() {
-				var fvs []string
-				if diag := msr.InstallFlags.ElementsAs(context.Background(), &fvs, true); diag == nil {
-					cc.Spec.MSR.InstallFlags = mcc_common_api.Flags(fvs)
-				}
-			}
-		} else {
-			diags.AddWarning("MSR configuration without hosts", "MSR configuration was provided, however there are no hosts with the MSR role provided. MSR installation is skippet.")
-		}
-	}
-
-	if !ls.Spec.MKE.InstallFlags.IsNull() {
-		var fvs []string
-		if diag := ls.Spec.MKE.InstallFlags.ElementsAs(context.Background(), &fvs, true); diag == nil {
-			cc.Spec.MKE.InstallFlags = mcc_common_api.Flags(fvs)
-		}
-	}
-	if !ls.Spec.MKE.UpgradeFlags.IsNull() {
-		var fvs []string
-		if diag := ls.Spec.MKE.UpgradeFlags.ElementsAs(context.Background(), &fvs, true); diag == nil {
-			cc.Spec.MKE.UpgradeFlags = mcc_common_api.Flags(fvs)
-		}
-	}
-
-	for _, host := range ls.Spec.Hosts {
-		mccHost := mcc_mke_api.Host{
-			Role:  host.Role.ValueString(),
-			Hooks: mcc_common_api.Hooks{},
-		}
-
-		if len(host.SSH) > 0 {
-			hssh := host.SSH[0]
-
-			mccHost.Connection = k0s_rig.Connection{
-				SSH: &k0s_rig.SSH{
-					Address: hssh.Address.ValueString(),
-					KeyPath: hssh.KeyPath.ValueStringPointer(),
-					User:    hssh.User.ValueString(),
-					Port:    int(hssh.Port.ValueInt64()),
-				},
-			}
-		} else if len(host.WinRM) > 0 {
-			hwinrm := host.WinRM[0]
-
-			mccHost.Connection = k0s_rig.Connection{
-				WinRM: &k0s_rig.WinRM{
-					Address:  hwinrm.Address.ValueString(),
-					Password: hwinrm.Password.ValueString(),
-					User:     hwinrm.User.ValueString(),
-					Port:     int(hwinrm.Port.ValueInt64()),
-					UseHTTPS: hwinrm.UseHTTPS.ValueBool(),
-					Insecure: hwinrm.Insecure.ValueBool(),
-				},
-			}
-		}
-
-		if len(host.Hooks) > 0 {
-			sh := host.Hooks[0]
-
-			if len(sh.Apply) > 0 {
-				ha := sh.Apply[0]
-
-				hha := map[string][]string{
-					"before": {},
-					"after":  {},
-				}
-				var shab []string
-				if diag := ha.Before.ElementsAs(context.Background(), &shab, true); diag == nil {
-					hha["before"] = shab
-				}
-				var shaa []string
-				if diag := ha.After.ElementsAs(context.Background(), &shaa, true); diag == nil {
-					hha["after"] = shab
-				}
-
-				mccHost.Hooks["apply"] = hha
-			}
-
-		}
-
-		cc.Spec.Hosts = append(cc.Spec.Hosts, &mccHost)
-	}
-
-	return cc, nil
-}
-
 type launchpadSchema14ModelMetadata struct {
 	Name types.String `tfsdk:"name" json:"name"`
 }
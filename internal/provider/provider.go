@@ -2,17 +2,33 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	//	"net/http"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
-	//	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
+	mcc_logrus "github.com/sirupsen/logrus"
 )
 
 const (
 	TestingVersion = "test"
+
+	DefaultApplyConcurrency = 10
+	MinApplyConcurrency     = 1
+	MaxApplyConcurrency     = 100
+	DefaultSSHUser          = "root"
+	DefaultSSHPort          = 22
+	DefaultWinRMUser        = "Administrator"
 )
 
 // Ensure ScaffoldingProvider satisfies various provider interfaces.
@@ -28,9 +44,73 @@ type LaunchpadProvider struct {
 
 // LaunchpadProviderModel describes the provider data model.
 type LaunchpadProviderModel struct {
+	SSHUser          types.String `tfsdk:"ssh_user"`
+	SSHKeyPath       types.String `tfsdk:"ssh_key_path"`
+	SSHPort          types.Int64  `tfsdk:"ssh_port"`
+	KnownHostsFile   types.String `tfsdk:"known_hosts_file"`
+	WinRMUser        types.String `tfsdk:"winrm_user"`
+	WinRMPassword    types.String `tfsdk:"winrm_password"`
+	ApplyConcurrency types.Int64  `tfsdk:"apply_concurrency"`
+	LogLevel         types.String `tfsdk:"log_level"`
+	DryRun           types.Bool   `tfsdk:"dry_run"`
+
+	SSHBastion []launchpadSSHBastionModel `tfsdk:"ssh_bastion"`
+
 	testingMode bool
 }
 
+// Concurrency how many hosts launchpad should work on at the same time.
+func (lpm *LaunchpadProviderModel) Concurrency() int {
+	if lpm == nil {
+		return DefaultApplyConcurrency
+	}
+	return int(int64ValueOr(lpm.ApplyConcurrency, DefaultApplyConcurrency))
+}
+
+// ApplyHostDefaults fill in host connection values that were not set on the resource, from the provider configuration.
+func (lpm *LaunchpadProviderModel) ApplyHostDefaults(cc *mcc_mke_api.ClusterConfig) {
+	d := LaunchpadProviderModel{}
+	if lpm != nil {
+		d = *lpm
+	}
+
+	for _, h := range cc.Spec.Hosts {
+		if hssh := h.SSH; hssh != nil {
+			if hssh.User == "" {
+				hssh.User = stringValueOr(d.SSHUser, DefaultSSHUser)
+			}
+			if hssh.KeyPath == nil && !(d.SSHKeyPath.IsNull() || d.SSHKeyPath.IsUnknown()) {
+				hssh.KeyPath = d.SSHKeyPath.ValueStringPointer()
+			}
+			if hssh.Port == 0 {
+				hssh.Port = int(int64ValueOr(d.SSHPort, DefaultSSHPort))
+			}
+			if hssh.Bastion == nil && len(d.SSHBastion) > 0 {
+				hssh.Bastion = d.SSHBastion[0].SSH()
+			}
+			if b := hssh.Bastion; b != nil {
+				if b.User == "" {
+					b.User = stringValueOr(d.SSHUser, DefaultSSHUser)
+				}
+				if b.KeyPath == nil && !(d.SSHKeyPath.IsNull() || d.SSHKeyPath.IsUnknown()) {
+					b.KeyPath = d.SSHKeyPath.ValueStringPointer()
+				}
+				if b.Port == 0 {
+					b.Port = DefaultSSHPort
+				}
+			}
+		}
+		if hwinrm := h.WinRM; hwinrm != nil {
+			if hwinrm.User == "" {
+				hwinrm.User = stringValueOr(d.WinRMUser, DefaultWinRMUser)
+			}
+			if hwinrm.Password == "" {
+				hwinrm.Password = d.WinRMPassword.ValueString()
+			}
+		}
+	}
+}
+
 func (p *LaunchpadProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "launchpad"
 	resp.Version = p.version
@@ -38,7 +118,101 @@ func (p *LaunchpadProvider) Metadata(ctx context.Context, req provider.MetadataR
 
 func (p *LaunchpadProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Attributes: map[string]schema.Attribute{},
+		MarkdownDescription: "Install Mirantis products using launchpad. Provider configuration holds defaults and execution settings shared by all launchpad resources.",
+
+		Attributes: map[string]schema.Attribute{
+			"ssh_user": schema.StringAttribute{
+				MarkdownDescription: "Default SSH user for hosts which do not set one",
+				Optional:            true,
+			},
+			"ssh_key_path": schema.StringAttribute{
+				MarkdownDescription: "Default SSH private key path for hosts which do not set one",
+				Optional:            true,
+			},
+			"ssh_port": schema.Int64Attribute{
+				MarkdownDescription: "Default SSH port for hosts which do not set one",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"known_hosts_file": schema.StringAttribute{
+				MarkdownDescription: "SSH known_hosts file which the host keys of ssh hosts and bastions without a host_key are verified against. Hosts which are not in it are refused, instead of being trusted on first use, and the file is never written to. Hosts behind a bastion cannot be checked against it, so they need a host_key",
+				Optional:            true,
+			},
+			"winrm_user": schema.StringAttribute{
+				MarkdownDescription: "Default WinRM user for hosts which do not set one",
+				Optional:            true,
+			},
+			"winrm_password": schema.StringAttribute{
+				MarkdownDescription: "Default WinRM password for hosts which do not set one",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"apply_concurrency": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("How many hosts launchpad works on in parallel (%d-%d, default %d)", MinApplyConcurrency, MaxApplyConcurrency, DefaultApplyConcurrency),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(MinApplyConcurrency, MaxApplyConcurrency),
+				},
+			},
+			"log_level": schema.StringAttribute{
+				MarkdownDescription: "Launchpad log level: trace, debug, info, warn or error",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("trace", "debug", "info", "warn", "error"),
+				},
+			},
+			"dry_run": schema.BoolAttribute{
+				MarkdownDescription: "Convert and validate the launchpad configuration, but do not run any launchpad installation, upgrade or reset",
+				Optional:            true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"ssh_bastion": schema.ListNestedBlock{
+				MarkdownDescription: "Default SSH bastion (jump host) for ssh hosts which do not set one",
+
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"address": schema.StringAttribute{
+							MarkdownDescription: "Bastion SSH endpoint",
+							Required:            true,
+						},
+						"user": schema.StringAttribute{
+							MarkdownDescription: "Bastion SSH user, defaults to the ssh_user",
+							Optional:            true,
+						},
+						"port": schema.Int64Attribute{
+							MarkdownDescription: "Bastion SSH port, defaults to 22",
+							Optional:            true,
+							Validators: []validator.Int64{
+								int64validator.Between(1, 65535),
+							},
+						},
+						"key_path": schema.StringAttribute{
+							MarkdownDescription: "Bastion SSH private key path, defaults to the ssh_key_path",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("private_key")),
+							},
+						},
+						"private_key": schema.StringAttribute{
+							MarkdownDescription: "Bastion SSH private key (PEM), instead of a key_path",
+							Optional:            true,
+							Sensitive:           true,
+						},
+						"host_key": schema.StringAttribute{
+							MarkdownDescription: "Pinned bastion SSH host key, as an authorized_keys line or an ssh-keyscan line, which is verified before launchpad connects. Defaults to the provider known_hosts_file",
+							Optional:            true,
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -51,8 +225,37 @@ func (p *LaunchpadProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
-	if p.version == TestingVersion {
-		data.testingMode = true
+	if p.version == TestingVersion && data.DryRun.IsNull() {
+		// acceptance testing never runs launchpad unless explicitly told to
+		data.DryRun = types.BoolValue(true)
+	}
+	data.testingMode = data.DryRun.ValueBool()
+
+	for _, b := range data.SSHBastion {
+		if hk := b.HostKey.ValueString(); hk != "" {
+			if _, err := launchpadHostKey(hk); err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("ssh_bastion").AtListIndex(0).AtName("host_key"),
+					"Invalid bastion host key",
+					err.Error(),
+				)
+
+				return
+			}
+		}
+	}
+
+	if !data.LogLevel.IsNull() {
+		level, err := mcc_logrus.ParseLevel(data.LogLevel.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid launchpad log level",
+				err.Error(),
+			)
+
+			return
+		}
+		mcc_logrus.SetLevel(level)
 	}
 
 	resp.ResourceData = &data
@@ -76,3 +279,19 @@ func New(version string) func() provider.Provider {
 		}
 	}
 }
+
+// stringValueOr the string value, or a default if it is null or unknown.
+func stringValueOr(v types.String, d string) string {
+	if v.IsNull() || v.IsUnknown() {
+		return d
+	}
+	return v.ValueString()
+}
+
+// int64ValueOr the int64 value, or a default if it is null or unknown.
+func int64ValueOr(v types.Int64, d int64) int64 {
+	if v.IsNull() || v.IsUnknown() {
+		return d
+	}
+	return v.ValueInt64()
+}
@@ -4,7 +4,11 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	mcc_mke_api "github.com/Mirantis/mcc/pkg/product/mke/api"
+	k0s_rig "github.com/k0sproject/rig"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -20,3 +24,57 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+func TestLaunchpadProviderModelApplyHostDefaults(t *testing.T) {
+	keyPath := "./host.pem"
+	cc := mcc_mke_api.ClusterConfig{
+		Spec: &mcc_mke_api.ClusterSpec{
+			Hosts: mcc_mke_api.Hosts{
+				{Connection: k0s_rig.Connection{SSH: &k0s_rig.SSH{Address: "manager1.example.org"}}},
+				{Connection: k0s_rig.Connection{SSH: &k0s_rig.SSH{Address: "worker1.example.org", User: "centos", KeyPath: &keyPath, Port: 2222}}},
+				{Connection: k0s_rig.Connection{WinRM: &k0s_rig.WinRM{Address: "windowsworker1.example.org"}}},
+				{Connection: k0s_rig.Connection{SSH: &k0s_rig.SSH{Address: "worker2.example.org", Bastion: &k0s_rig.SSH{Address: "jump2.example.org", Port: 2200}}}},
+			},
+		},
+	}
+
+	lpm := &LaunchpadProviderModel{
+		SSHUser:       types.StringValue("ubuntu"),
+		SSHKeyPath:    types.StringValue("./key.pem"),
+		SSHPort:       types.Int64Null(),
+		WinRMPassword: types.StringValue("my-win-password"),
+		SSHBastion: []launchpadSSHBastionModel{{
+			Address:    types.StringValue("jump1.example.org"),
+			User:       types.StringNull(),
+			Port:       types.Int64Null(),
+			KeyPath:    types.StringNull(),
+			PrivateKey: types.StringNull(),
+		}},
+	}
+	lpm.ApplyHostDefaults(&cc)
+
+	defaulted := cc.Spec.Hosts[0].SSH
+	if defaulted.User != "ubuntu" || defaulted.KeyPath == nil || *defaulted.KeyPath != "./key.pem" || defaulted.Port != DefaultSSHPort {
+		t.Errorf("provider defaults not applied to ssh host: %+v", defaulted)
+	}
+
+	explicit := cc.Spec.Hosts[1].SSH
+	if explicit.User != "centos" || *explicit.KeyPath != keyPath || explicit.Port != 2222 {
+		t.Errorf("provider defaults overrode host ssh values: %+v", explicit)
+	}
+
+	winrm := cc.Spec.Hosts[2].WinRM
+	if winrm.User != DefaultWinRMUser || winrm.Password != "my-win-password" {
+		t.Errorf("provider defaults not applied to winrm host: %+v", winrm)
+	}
+
+	bastion := cc.Spec.Hosts[0].SSH.Bastion
+	if bastion == nil || bastion.Address != "jump1.example.org" || bastion.User != "ubuntu" || *bastion.KeyPath != "./key.pem" || bastion.Port != DefaultSSHPort {
+		t.Errorf("provider bastion not applied to ssh host: %+v", bastion)
+	}
+
+	own := cc.Spec.Hosts[3].SSH.Bastion
+	if own.Address != "jump2.example.org" || own.Port != 2200 || own.User != "ubuntu" {
+		t.Errorf("provider bastion overrode the host bastion: %+v", own)
+	}
+}